@@ -0,0 +1,286 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Top-Ranger/responsego/deck"
+	"github.com/Top-Ranger/responsego/registry"
+)
+
+const (
+	maxAgendaItems    = 200
+	maxAgendaDuration = 24 * 60 * 60 // seconds
+)
+
+// agendaItem is a single element of the agenda.
+// Duration is the number of seconds after which the next element is activated automatically, 0 disables automatic advancing.
+// If AfterClose is true, the element is advanced after the poll is closed. Duration is then counted from closing the poll.
+type agendaItem struct {
+	From        string
+	Data        string
+	Description string
+	Duration    int
+	AfterClose  bool
+}
+
+// agendaStatus is send to the admins whenever the agenda changes.
+// Position is -1 if no element of the agenda is active.
+// Remaining is the number of seconds until the next element is activated or -1 if no automatic advance is scheduled.
+type agendaStatus struct {
+	Items     []agendaItem
+	Position  int
+	Remaining int
+}
+
+// validateAgenda checks all elements of an agenda.
+func validateAgenda(items []agendaItem) error {
+	if len(items) > maxAgendaItems {
+		return fmt.Errorf("at most %d elements allowed", maxAgendaItems)
+	}
+	for i := range items {
+		if _, ok := registry.GetFeedbackPlugins(items[i].From); !ok {
+			return fmt.Errorf("element %d: unknown plugin %s", i+1, items[i].From)
+		}
+		if items[i].Duration < 0 || items[i].Duration > maxAgendaDuration {
+			return fmt.Errorf("element %d: invalid duration %d", i+1, items[i].Duration)
+		}
+	}
+	return nil
+}
+
+// setAgenda replaces the agenda. The current position is kept if it is still valid. Caller must hold r.l.
+func (r *response) setAgenda(items []agendaItem) error {
+	err := validateAgenda(items)
+	if err != nil {
+		return err
+	}
+	r.agenda = items
+	if r.agendaPosition >= len(items) {
+		r.agendaPosition = -1
+		r.agendaPlugin = nil
+	}
+	r.sendAgendaStatus()
+	return nil
+}
+
+// agendaGoto activates the element at position. Caller must hold r.l.
+func (r *response) agendaGoto(position int) error {
+	if position < 0 || position >= len(r.agenda) {
+		return fmt.Errorf("invalid position %d", position)
+	}
+	// The status is send before activation so that admins can reset the page before receiving the new plugin
+	oldPosition := r.agendaPosition
+	r.agendaPosition = position
+	r.agendaPlugin = nil
+	r.sendAgendaStatus()
+
+	item := r.agenda[position]
+	err := r.activatePlugin(item.From, item.Data)
+	if err != nil {
+		r.agendaPosition = oldPosition
+		r.agendaPlugin = r.currentPlugin
+		r.sendAgendaStatus()
+		return err
+	}
+	r.agendaPlugin = r.currentPlugin
+	r.agendaSince = time.Now()
+	r.agendaClosed = false
+	r.sendAgendaStatus()
+	return nil
+}
+
+// agendaStep moves delta elements through the agenda. If the agenda was not started, the first element is activated. Caller must hold r.l.
+func (r *response) agendaStep(delta int) error {
+	if len(r.agenda) == 0 {
+		return errors.New("agenda is empty")
+	}
+	if r.agendaPosition == -1 {
+		return r.agendaGoto(0)
+	}
+	return r.agendaGoto(r.agendaPosition + delta)
+}
+
+// agendaStop stops the agenda. The current plugin stays active. Caller must hold r.l.
+func (r *response) agendaStop() {
+	r.agendaPosition = -1
+	r.agendaPlugin = nil
+	r.sendAgendaStatus()
+}
+
+// agendaRemaining returns the seconds until the next element is activated automatically or -1. Caller must hold r.l.
+func (r *response) agendaRemaining() int {
+	if r.agendaPosition < 0 || r.agendaPosition+1 >= len(r.agenda) || r.currentPlugin != r.agendaPlugin {
+		return -1
+	}
+	item := r.agenda[r.agendaPosition]
+	if item.AfterClose && !r.agendaClosed {
+		return -1
+	}
+	if item.Duration == 0 && !item.AfterClose {
+		return -1
+	}
+	remaining := item.Duration - int(time.Since(r.agendaSince)/time.Second)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// getAgendaStatus returns the current status of the agenda. Caller must hold r.l.
+func (r *response) getAgendaStatus() agendaStatus {
+	items := r.agenda
+	if items == nil {
+		items = make([]agendaItem, 0)
+	}
+	return agendaStatus{Items: items, Position: r.agendaPosition, Remaining: r.agendaRemaining()}
+}
+
+// sendAgendaStatus sends the status of the agenda to all admins. Caller must hold r.l.
+func (r *response) sendAgendaStatus() {
+	d, err := json.Marshal(r.getAgendaStatus())
+	if err != nil {
+		log.Printf("sending agenda (%s): %s", r.Path, err.Error())
+		return
+	}
+	m := message{From: globalAction, Action: agendaData, Data: string(d)}
+	b, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("sending agenda (%s): %s", r.Path, err.Error())
+		return
+	}
+	for k := range r.admins {
+		select {
+		case r.admins[k] <- b:
+		default:
+		}
+	}
+}
+
+// agendaTick advances the agenda if the current element is finished.
+// Caller must not hold r.l, since plugins are queried whether they are closed.
+func (r *response) agendaTick() {
+	r.l.Lock()
+	if r.agendaPosition < 0 || r.currentPlugin != r.agendaPlugin || r.agendaPlugin == nil {
+		r.l.Unlock()
+		return
+	}
+	item := r.agenda[r.agendaPosition]
+	p := r.agendaPlugin
+	closed := r.agendaClosed
+	r.l.Unlock()
+
+	if item.AfterClose && !closed {
+		cp, ok := p.(registry.ClosablePlugin)
+		if !ok || !cp.IsClosed() {
+			return
+		}
+		r.l.Lock()
+		defer r.l.Unlock()
+		if r.agendaPlugin != p {
+			return
+		}
+		r.agendaClosed = true
+		r.agendaSince = time.Now()
+		r.sendAgendaStatus()
+		return
+	}
+
+	r.l.Lock()
+	defer r.l.Unlock()
+	if r.agendaPlugin != p || r.agendaRemaining() != 0 {
+		return
+	}
+	err := r.agendaStep(1)
+	if err != nil {
+		log.Printf("agenda (%s): %s", r.Path, err.Error())
+	}
+}
+
+// HandleAgenda allows to control the agenda through HTTP requests (e.g. by a presentation clicker).
+// The command is given through the 'agenda' parameter (next, previous, stop, goto, deck, status). goto requires the 'position' parameter (starting at 0).
+// deck replaces the agenda with the deck send as body of a POST request.
+// The status of the agenda is returned as JSON.
+func (r *response) HandleAgenda(rw http.ResponseWriter, req *http.Request) {
+	var body []byte
+	if req.URL.Query().Get("agenda") == "deck" {
+		if req.Method != http.MethodPost {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var err error
+		body, err = io.ReadAll(http.MaxBytesReader(rw, req.Body, maxDeckSizeKB*1024))
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	var err error
+	switch req.URL.Query().Get("agenda") {
+	case "next":
+		err = r.agendaStep(1)
+	case "previous":
+		err = r.agendaStep(-1)
+	case "stop":
+		r.agendaStop()
+	case "goto":
+		var position int
+		position, err = strconv.Atoi(req.URL.Query().Get("position"))
+		if err == nil {
+			err = r.agendaGoto(position)
+		}
+	case "deck":
+		items, errs := deck.Parse(body)
+		if len(errs) != 0 {
+			messages := make([]string, len(errs))
+			for i := range errs {
+				messages[i] = errs[i].Error()
+			}
+			err = errors.New(strings.Join(messages, "\n"))
+			break
+		}
+		err = r.setAgenda(agendaFromDeck(items))
+	case "status":
+	default:
+		http.Error(rw, "unknown command", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	b, err := json.Marshal(r.getAgendaStatus())
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(b)
+}
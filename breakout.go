@@ -0,0 +1,355 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/Top-Ranger/responsego/registry"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+const breakoutAdmin = `
+<h1>{{.Translation.BreakoutRooms}}: {{.Plugin}}</h1>
+<div class="breakout">
+{{range $i, $e := .Rooms}}
+<div class="breakoutroom">
+<h2>{{$.Translation.BreakoutRoom}} {{$e.Name}} ({{len $e.Members}})</h2>
+<iframe class="breakoutframe" data-room="{{$e.Name}}"></iframe>
+</div>
+{{end}}
+</div>
+`
+
+var breakoutAdminTemplate = template.Must(template.New("breakoutAdmin").Parse(breakoutAdmin))
+
+type breakoutAdminStruct struct {
+	Plugin      string
+	Rooms       []*breakoutRoom
+	Translation translation.Translation
+}
+
+const breakoutUser = `
+<h1>{{.Translation.BreakoutRooms}}</h1>
+<p>{{.Translation.NoBreakoutRoom}}</p>
+`
+
+var breakoutUserTemplate = template.Must(template.New("breakoutUser").Parse(breakoutUser))
+
+type breakoutUserStruct struct {
+	Translation translation.Translation
+}
+
+// breakoutRoom represents a plugin which is only active for a subset of the participants.
+type breakoutRoom struct {
+	Name    string
+	Members map[string]bool
+
+	plugin registry.FeedbackPlugin
+	cancel context.CancelFunc
+
+	adminHTML        chan template.HTML
+	userHTML         chan template.HTML
+	adminData        chan []byte
+	userData         chan []byte
+	adminInput       chan []byte
+	userInput        chan []byte
+	participantInput chan registry.ParticipantInput
+	participantData  chan registry.ParticipantOutput
+}
+
+// newBreakoutRoom creates a room with its own instance of the plugin. The plugin is not activated.
+func newBreakoutRoom(name string, fp func() registry.FeedbackPlugin) *breakoutRoom {
+	room := &breakoutRoom{
+		Name:       name,
+		Members:    make(map[string]bool),
+		adminHTML:  make(chan template.HTML, bufferSize),
+		userHTML:   make(chan template.HTML, bufferSize),
+		adminInput: make(chan []byte, bufferSize),
+		userInput:  make(chan []byte, bufferSize),
+	}
+
+	p := fp()
+	p.AdminHTMLChannel(room.adminHTML)
+	p.UserHTMLChannel(room.userHTML)
+	p.ReceiveAdminChannel(room.adminInput)
+	p.ReceiveUserChannel(room.userInput)
+	if p, ok := p.(registry.DataFeedbackPlugin); ok {
+		room.adminData = make(chan []byte, bufferSize)
+		room.userData = make(chan []byte, bufferSize)
+		p.AdminDataChannel(room.adminData)
+		p.UserDataChannel(room.userData)
+	}
+	if p, ok := p.(registry.ParticipantFeedbackPlugin); ok {
+		room.participantInput = make(chan registry.ParticipantInput, bufferSize)
+		p.ReceiveUserParticipantChannel(room.participantInput)
+	}
+	if p, ok := p.(registry.ParticipantDataFeedbackPlugin); ok {
+		room.participantData = make(chan registry.ParticipantOutput, bufferSize)
+		p.UserParticipantDataChannel(room.participantData)
+	}
+	room.plugin = p
+	return room
+}
+
+// sortRoomNames sorts room names. Numeric names are sorted by their value and before all other names.
+func sortRoomNames(names []string) {
+	sort.Slice(names, func(i, j int) bool {
+		a, errA := strconv.Atoi(names[i])
+		b, errB := strconv.Atoi(names[j])
+		switch {
+		case errA == nil && errB == nil:
+			return a < b
+		case errA == nil:
+			return true
+		case errB == nil:
+			return false
+		}
+		return names[i] < names[j]
+	})
+}
+
+// newBreakoutRooms activates the plugin separately for each group without installing the rooms.
+// groups maps participants to group names.
+// If an error occurs, all rooms already activated are deactivated again.
+func newBreakoutRooms(pluginName string, data []byte, groups map[string]string) ([]*breakoutRoom, error) {
+	if len(groups) == 0 {
+		return nil, errors.New("no groups available")
+	}
+	fp, ok := registry.GetFeedbackPlugins(pluginName)
+	if !ok {
+		return nil, fmt.Errorf("unknown plugin %s", pluginName)
+	}
+
+	rooms := make(map[string]*breakoutRoom)
+	for participant, name := range groups {
+		room, ok := rooms[name]
+		if !ok {
+			room = newBreakoutRoom(name, fp)
+			rooms[name] = room
+		}
+		room.Members[participant] = true
+	}
+
+	names := make([]string, 0, len(rooms))
+	for name := range rooms {
+		names = append(names, name)
+	}
+	sortRoomNames(names)
+
+	result := make([]*breakoutRoom, 0, len(names))
+	for _, name := range names {
+		room := rooms[name]
+		err := room.plugin.Activate(data)
+		if err != nil {
+			for i := range result {
+				result[i].plugin.Deactivate()
+			}
+			return nil, fmt.Errorf("room %s: %w", name, err)
+		}
+		result = append(result, room)
+	}
+	return result, nil
+}
+
+// startBreakout installs rooms created by newBreakoutRooms.
+// Caller must hold r.l and must have deactivated the current plugin and all previous rooms.
+func (r *response) startBreakout(pluginName string, rooms []*breakoutRoom) {
+	r.breakouts = rooms
+	for _, room := range rooms {
+		ctx, cancel := context.WithCancel(r.ctx)
+		room.cancel = cancel
+		go r.breakoutWorker(ctx, room)
+	}
+	r.breakoutPluginName = pluginName
+}
+
+// stopBreakout deactivates all breakout rooms. Caller must hold r.l.
+func (r *response) stopBreakout() {
+	for _, room := range r.breakouts {
+		room.plugin.Deactivate()
+		if room.cancel != nil {
+			room.cancel()
+		}
+	}
+	r.breakouts = nil
+	r.breakoutPluginName = ""
+}
+
+// breakoutRoomOf returns the room of a participant or nil. Caller must hold r.l.
+func (r *response) breakoutRoomOf(participant string) *breakoutRoom {
+	if participant == "" {
+		return nil
+	}
+	for _, room := range r.breakouts {
+		if room.Members[participant] {
+			return room
+		}
+	}
+	return nil
+}
+
+// breakoutRoomByName returns the room with the given name or nil. Caller must hold r.l.
+func (r *response) breakoutRoomByName(name string) *breakoutRoom {
+	for _, room := range r.breakouts {
+		if room.Name == name {
+			return room
+		}
+	}
+	return nil
+}
+
+// breakoutAdminHTML returns the overview shown to admins while breakout rooms are active. Caller must hold r.l.
+func (r *response) breakoutAdminHTML() template.HTML {
+	td := breakoutAdminStruct{
+		Plugin:      r.breakoutPluginName,
+		Rooms:       r.breakouts,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := breakoutAdminTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("breakout admin (%s): %s", r.Path, err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+// breakoutUserHTML returns the HTML for a participant while breakout rooms are active. Caller must hold r.l.
+func (r *response) breakoutUserHTML(participant string) template.HTML {
+	room := r.breakoutRoomOf(participant)
+	if room != nil {
+		return room.plugin.GetLastHTMLUser()
+	}
+
+	td := breakoutUserStruct{
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := breakoutUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("breakout user (%s): %s", r.Path, err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+// breakoutAdminMessages returns all messages an admin needs to display the breakout rooms. Caller must hold r.l.
+func (r *response) breakoutAdminMessages() []message {
+	messages := []message{{From: r.breakoutPluginName, Action: actionHTML, Data: string(r.breakoutAdminHTML())}}
+	for _, room := range r.breakouts {
+		messages = append(messages, message{From: r.breakoutPluginName, Action: actionHTML, Data: string(room.plugin.GetLastHTMLAdmin()), Room: room.Name})
+	}
+	if _, ok := r.breakouts[0].plugin.(registry.DownloadResultPlugin); ok {
+		messages = append(messages, message{From: globalAction, Action: canDownload, Data: "[]"})
+	}
+	return messages
+}
+
+// sendBreakoutAdmin sends a message of a room to all admins. Caller must hold r.l.
+func (r *response) sendBreakoutAdmin(room *breakoutRoom, action, data string) {
+	m := message{From: r.breakoutPluginName, Action: action, Data: data, Room: room.Name}
+	b, err := json.Marshal(&m)
+	if err != nil {
+		log.Printf("breakout admin %s (%s) room %s: %s", action, r.Path, room.Name, err.Error())
+		return
+	}
+	for k := range r.admins {
+		select {
+		case r.admins[k] <- b:
+		default:
+		}
+	}
+}
+
+// sendBreakoutUser sends a message to all members of a room.
+// If participant is not empty, the message is only send to this participant.
+// Caller must hold r.l.
+func (r *response) sendBreakoutUser(room *breakoutRoom, participant, action, data string) {
+	m := message{From: r.breakoutPluginName, Action: action, Data: data}
+	b, err := json.Marshal(&m)
+	if err != nil {
+		log.Printf("breakout user %s (%s) room %s: %s", action, r.Path, room.Name, err.Error())
+		return
+	}
+	for k := range r.users {
+		p := r.participants[k]
+		if !room.Members[p] || (participant != "" && p != participant) {
+			continue
+		}
+		select {
+		case r.users[k] <- b:
+		default:
+		}
+	}
+}
+
+// breakoutWorker forwards all outputs of the plugin of a room to the connections of the room.
+func (r *response) breakoutWorker(ctx context.Context, room *breakoutRoom) {
+	done := ctx.Done()
+	for {
+		select {
+		case t := <-room.adminHTML:
+			r.l.Lock()
+			r.sendBreakoutAdmin(room, actionHTML, string(t))
+			r.l.Unlock()
+		case t := <-room.userHTML:
+			r.l.Lock()
+			r.sendBreakoutUser(room, "", actionHTML, string(t))
+			r.l.Unlock()
+		case t := <-room.adminData:
+			r.l.Lock()
+			r.sendBreakoutAdmin(room, actionData, string(t))
+			r.l.Unlock()
+		case t := <-room.userData:
+			r.l.Lock()
+			r.sendBreakoutUser(room, "", actionData, string(t))
+			r.l.Unlock()
+		case t := <-room.participantData:
+			if t.Participant == "" {
+				continue
+			}
+			r.l.Lock()
+			r.sendBreakoutUser(room, t.Participant, actionData, string(t.Data))
+			r.l.Unlock()
+		case <-done:
+			return
+		}
+	}
+}
+
+// breakoutDownload returns the downloads of all rooms. Caller must hold r.l.
+func (r *response) breakoutDownload() []byte {
+	result := make(map[string]string, len(r.breakouts))
+	for _, room := range r.breakouts {
+		dp, ok := room.plugin.(registry.DownloadResultPlugin)
+		if !ok {
+			continue
+		}
+		result[room.Name] = string(dp.GetAdminDownload())
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return []byte(err.Error())
+	}
+	return b
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/Top-Ranger/responsego/deck"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+const maxDeckSizeKB = 1024
+
+// agendaFromDeck converts the items of a deck into an agenda.
+func agendaFromDeck(items []deck.Item) []agendaItem {
+	agenda := make([]agendaItem, len(items))
+	for i := range items {
+		agenda[i] = agendaItem{From: items[i].From, Data: items[i].Data, Description: items[i].Description, Duration: items[i].Duration, AfterClose: items[i].AfterClose}
+	}
+	return agenda
+}
+
+// deckCommand implements the 'deck' subcommand.
+// It validates decks and optionally prints the resulting agenda. The exit code is returned.
+func deckCommand(args []string) int {
+	fs := flag.NewFlagSet("deck", flag.ExitOnError)
+	language := fs.String("language", "en", "Language used for the descriptions of the elements")
+	printJSON := fs.Bool("json", false, "Print the agenda of all valid decks as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s deck [options] file...\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Validates decks of ResponseGo!. If no file is given, stdin is read.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	logger := log.New(os.Stderr, "", 0)
+	err := translation.SetDefaultTranslation(*language)
+	if err != nil {
+		logger.Printf("deck: can not set language %s: %s", *language, err.Error())
+		return 2
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	code := 0
+	agenda := make([]agendaItem, 0)
+	for _, f := range files {
+		var b []byte
+		if f == "-" {
+			b, err = io.ReadAll(os.Stdin)
+		} else {
+			b, err = os.ReadFile(f)
+		}
+		if err != nil {
+			logger.Printf("deck: can not read %s: %s", f, err.Error())
+			return 2
+		}
+		items, errs := deck.Parse(b)
+		if len(errs) == 0 {
+			err = validateAgenda(agendaFromDeck(items))
+			if err != nil {
+				errs = append(errs, deck.Error{Line: 1, Column: 1, Message: err.Error()})
+			}
+		}
+		for _, e := range errs {
+			logger.Printf("%s:%d:%d: %s", f, e.Line, e.Column, e.Message)
+		}
+		if len(errs) != 0 {
+			code = 1
+			continue
+		}
+		if !*printJSON {
+			fmt.Printf("%s: %d elements\n", f, len(items))
+		}
+		agenda = append(agenda, agendaFromDeck(items)...)
+	}
+
+	if *printJSON {
+		b, err := json.Marshal(agenda)
+		if err != nil {
+			logger.Printf("deck: %s", err.Error())
+			return 1
+		}
+		os.Stdout.Write(b)
+		os.Stdout.Write([]byte("\n"))
+	}
+	return code
+}
@@ -0,0 +1,558 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package deck parses decks. A deck is a Markdown file defining all interactive elements of a session in order.
+//
+// Each element starts with a level two heading "## <kind> <question>". Everything before the first element,
+// e.g. a level one title, is ignored. The following kinds are supported:
+//
+//	poll      Question, the answers are given as a Markdown list
+//	multiple  MultipleChoice, the answers are given as a Markdown list
+//	freetext  FreeText
+//	number    Number
+//	text      TextDisplay, the content of the element is Markdown and is shown as it is
+//	element   Any plugin, the heading contains the plugin name and the configuration is given as a fenced code block
+//
+// If the heading does not contain the question, all text lines before the first answer form the question.
+// Elements can be configured with lines of the form "key: value":
+//
+//	duration     seconds (or a duration like "2m") after which the next element is activated
+//	after-close  if true, the duration is counted from closing the element
+//	description  description of the element in the agenda
+//	time-limit   time limit in seconds (poll, multiple, number)
+//	reveal       who sees the results: admin (default), live, hidden or never (poll, multiple)
+//	min, max     allowed range (number)
+//	correct      correct value (number)
+//	decimal      if true, decimal numbers are allowed (number)
+//
+// In text elements, configuration lines are only recognised directly after the heading.
+//
+// Example:
+//
+//	# Lecture 3
+//
+//	## text
+//	Welcome to **lecture 3**!
+//
+//	## poll Which colour do you prefer?
+//	- Red
+//	- Blue
+//	duration: 60
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Top-Ranger/responsego/helper"
+	"github.com/Top-Ranger/responsego/registry"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+const (
+	// MaxItems is the maximum number of elements in a deck.
+	MaxItems = 200
+
+	maxDuration          = 24 * time.Hour
+	maxDescriptionLength = 80
+)
+
+// Item is a single element of a deck. It can be used directly as an element of an agenda.
+type Item struct {
+	From        string
+	Data        string
+	Description string
+	Duration    int
+	AfterClose  bool
+}
+
+// Error describes a problem of a deck. Line and Column start at 1.
+type Error struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error returns a human readable version of the error.
+func (e Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+var (
+	headingRegexp   = regexp.MustCompile(`^##[ \t]+(\S+)[ \t]*(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	answerRegexp    = regexp.MustCompile(`^[ \t]*(?:[-*+]|\d+[.)])[ \t]+(.*?)[ \t]*$`)
+	attributeRegexp = regexp.MustCompile(`^(after-close|correct|decimal|description|duration|max|min|reveal|time-limit):[ \t]*(.*?)[ \t]*$`)
+	fenceRegexp     = regexp.MustCompile("^[ \t]*(```|~~~)")
+)
+
+// kinds contains all supported kinds together with the allowed attributes.
+var kinds = map[string]map[string]bool{
+	"poll":     {"time-limit": true, "reveal": true},
+	"multiple": {"time-limit": true, "reveal": true},
+	"freetext": {},
+	"number":   {"time-limit": true, "min": true, "max": true, "correct": true, "decimal": true},
+	"text":     {},
+	"element":  {},
+}
+
+// line is a single line of the input.
+type line struct {
+	number int
+	text   string
+}
+
+// section is a single element of the deck which is currently parsed.
+type section struct {
+	heading  line
+	kind     string
+	title    string
+	titleCol int
+	lines    []line
+}
+
+// Parse parses a deck. All problems found are returned, the items are only valid if no errors are returned.
+func Parse(b []byte) ([]Item, []Error) {
+	s := strings.ReplaceAll(string(b), "\r\n", "\n")
+	s = strings.TrimPrefix(s, "\ufeff")
+
+	items := make([]Item, 0)
+	errs := make([]Error, 0)
+
+	if !utf8.ValidString(s) {
+		return items, []Error{{Line: 1, Column: 1, Message: "input is not valid UTF-8"}}
+	}
+
+	var current *section
+	fence := ""
+	for i, l := range strings.Split(s, "\n") {
+		if current != nil {
+			if m := fenceRegexp.FindStringSubmatch(l); m != nil {
+				if fence == "" {
+					fence = m[1]
+				} else if fence == m[1] {
+					fence = ""
+				}
+			}
+		}
+		if fence == "" {
+			if m := headingRegexp.FindStringSubmatchIndex(l); m != nil {
+				if current != nil {
+					item, e := current.parse()
+					items = append(items, item...)
+					errs = append(errs, e...)
+				}
+				current = &section{
+					heading:  line{number: i + 1, text: l},
+					kind:     l[m[2]:m[3]],
+					title:    l[m[4]:m[5]],
+					titleCol: utf8.RuneCountInString(l[:m[4]]) + 1,
+				}
+				continue
+			}
+		}
+		if current != nil {
+			current.lines = append(current.lines, line{number: i + 1, text: l})
+		}
+	}
+	if current != nil {
+		if fence != "" {
+			errs = append(errs, Error{Line: current.heading.number, Column: 1, Message: "unclosed code block"})
+		}
+		item, e := current.parse()
+		items = append(items, item...)
+		errs = append(errs, e...)
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+
+	if len(items) == 0 && len(errs) == 0 {
+		errs = append(errs, Error{Line: 1, Column: 1, Message: "no elements found"})
+	}
+	if len(items) > MaxItems {
+		errs = append(errs, Error{Line: 1, Column: 1, Message: fmt.Sprintf("%d elements found, at most %d are allowed", len(items), MaxItems)})
+	}
+	return items, errs
+}
+
+// errorAt returns an error pointing to the given byte offset of the line.
+func (l line) errorAt(offset int, format string, a ...interface{}) Error {
+	if offset > len(l.text) {
+		offset = len(l.text)
+	}
+	return Error{Line: l.number, Column: utf8.RuneCountInString(l.text[:offset]) + 1, Message: fmt.Sprintf(format, a...)}
+}
+
+// valueOffset returns the byte offset of the value of an attribute line.
+func (l line) valueOffset() int {
+	m := attributeRegexp.FindStringSubmatchIndex(l.text)
+	if m == nil {
+		return 0
+	}
+	return m[4]
+}
+
+// parse converts the section into an item. Either an item or errors are returned.
+func (s *section) parse() ([]Item, []Error) {
+	allowed, ok := kinds[s.kind]
+	if !ok {
+		return nil, []Error{s.heading.errorAt(3, "unknown kind %s", s.kind)}
+	}
+
+	errs := make([]Error, 0)
+	item := Item{}
+	attributes := make(map[string]string)
+	attributeLines := make(map[string]line)
+	text := make([]line, 0)
+	answers := make([]line, 0)
+	code := make([]string, 0)
+	codeFound := false
+	fence := ""
+	content := false
+
+	for _, l := range s.lines {
+		if s.kind == "text" {
+			if !content {
+				if m := attributeRegexp.FindStringSubmatch(l.text); m != nil {
+					errs = append(errs, s.addAttribute(attributes, attributeLines, allowed, l, m[1], m[2])...)
+					continue
+				}
+				if strings.TrimSpace(l.text) == "" {
+					continue
+				}
+			}
+			content = true
+			text = append(text, l)
+			continue
+		}
+
+		if s.kind == "element" {
+			if m := fenceRegexp.FindStringSubmatch(l.text); m != nil {
+				switch {
+				case fence == "" && codeFound:
+					errs = append(errs, l.errorAt(0, "only one code block allowed"))
+					fence = m[1]
+				case fence == "":
+					fence = m[1]
+					codeFound = true
+				case fence == m[1]:
+					fence = ""
+				default:
+					code = append(code, l.text)
+				}
+				continue
+			}
+			if fence != "" {
+				code = append(code, l.text)
+				continue
+			}
+		}
+
+		if strings.TrimSpace(l.text) == "" {
+			continue
+		}
+		if m := attributeRegexp.FindStringSubmatch(l.text); m != nil {
+			errs = append(errs, s.addAttribute(attributes, attributeLines, allowed, l, m[1], m[2])...)
+			continue
+		}
+		if m := answerRegexp.FindStringSubmatch(l.text); m != nil && (s.kind == "poll" || s.kind == "multiple") {
+			answers = append(answers, l)
+			continue
+		}
+		if len(answers) != 0 {
+			errs = append(errs, l.errorAt(0, "text after answers"))
+			continue
+		}
+		text = append(text, l)
+	}
+
+	tl := translation.GetDefaultTranslation()
+	var err []Error
+	switch s.kind {
+	case "poll", "multiple":
+		item, err = s.choice(text, answers, attributes, attributeLines)
+	case "freetext":
+		var question string
+		question, err = s.question(text)
+		item = Item{From: "FreeText", Data: question, Description: describe(tl.DisplayFreeText, question)}
+	case "number":
+		item, err = s.number(text, attributes, attributeLines)
+	case "text":
+		item, err = s.text(text)
+	case "element":
+		item, err = s.element(text, code, codeFound)
+	}
+	errs = append(errs, err...)
+
+	if d, ok := attributes["description"]; ok {
+		item.Description = d
+	}
+	if d, ok := attributes["duration"]; ok {
+		l := attributeLines["duration"]
+		duration, e := parseDuration(d)
+		if e != nil {
+			errs = append(errs, l.errorAt(l.valueOffset(), "invalid duration: %s", e.Error()))
+		}
+		item.Duration = duration
+	}
+	if a, ok := attributes["after-close"]; ok {
+		l := attributeLines["after-close"]
+		afterClose, e := parseBool(a)
+		if e != nil {
+			errs = append(errs, l.errorAt(l.valueOffset(), "invalid value for after-close: %s", e.Error()))
+		}
+		item.AfterClose = afterClose
+	}
+
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return []Item{item}, nil
+}
+
+// addAttribute stores a single attribute after checking that it is allowed.
+func (s *section) addAttribute(attributes map[string]string, lines map[string]line, allowed map[string]bool, l line, key, value string) []Error {
+	switch key {
+	case "duration", "after-close", "description":
+	default:
+		if !allowed[key] {
+			return []Error{l.errorAt(0, "attribute %s is not supported for %s", key, s.kind)}
+		}
+	}
+	if previous, ok := lines[key]; ok {
+		return []Error{l.errorAt(0, "attribute %s already set in line %d", key, previous.number)}
+	}
+	attributes[key] = value
+	lines[key] = l
+	return nil
+}
+
+// question returns the question of the section, either from the heading or from the text.
+func (s *section) question(text []line) (string, []Error) {
+	parts := make([]string, 0, len(text)+1)
+	if s.title != "" {
+		if len(text) != 0 {
+			return "", []Error{text[0].errorAt(0, "question already given in heading")}
+		}
+		parts = append(parts, s.title)
+	}
+	for i := range text {
+		parts = append(parts, strings.TrimSpace(text[i].text))
+	}
+	question := strings.Join(parts, " ")
+	if question == "" {
+		return "", []Error{s.heading.errorAt(len(s.heading.text), "no question found")}
+	}
+	return question, nil
+}
+
+// choice returns a Question or MultipleChoice item.
+func (s *section) choice(text, answers []line, attributes map[string]string, attributeLines map[string]line) (Item, []Error) {
+	question, errs := s.question(text)
+	data := map[string]string{"q": question}
+
+	if len(answers) == 0 {
+		errs = append(errs, s.heading.errorAt(0, "no answers found"))
+	}
+	options := make([]string, len(answers))
+	for i, a := range answers {
+		options[i] = answerRegexp.FindStringSubmatch(a.text)[1]
+	}
+	errs = append(errs, timeLimit(data, attributes, attributeLines)...)
+	if r, ok := attributes["reveal"]; ok {
+		switch r {
+		case "admin":
+		case "live", "hidden", "never":
+			data["r"] = r
+		default:
+			l := attributeLines["reveal"]
+			errs = append(errs, l.errorAt(l.valueOffset(), "unknown reveal mode %s", r))
+		}
+	}
+
+	config := map[string]interface{}{"o": options}
+	for k, v := range data {
+		config[k] = v
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		errs = append(errs, s.heading.errorAt(0, "%s", err.Error()))
+	}
+
+	tl := translation.GetDefaultTranslation()
+	if s.kind == "multiple" {
+		return Item{From: "MultipleChoice", Data: string(b), Description: describe(tl.DisplayMultipleChoice, question)}, errs
+	}
+	return Item{From: "Question", Data: string(b), Description: describe(tl.DisplayQuestion, question)}, errs
+}
+
+// number returns a Number item.
+func (s *section) number(text []line, attributes map[string]string, attributeLines map[string]line) (Item, []Error) {
+	question, errs := s.question(text)
+	data := map[string]string{"q": question}
+
+	values := make(map[string]float64)
+	keys := map[string]string{"min": "min", "max": "max", "correct": "c"}
+	for _, key := range []string{"min", "max", "correct"} {
+		v, ok := attributes[key]
+		if !ok {
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			l := attributeLines[key]
+			errs = append(errs, l.errorAt(l.valueOffset(), "%s is not a number", v))
+			continue
+		}
+		values[key] = f
+		data[keys[key]] = v
+	}
+	if min, ok := values["min"]; ok {
+		if max, ok := values["max"]; ok && min > max {
+			l := attributeLines["max"]
+			errs = append(errs, l.errorAt(l.valueOffset(), "maximum is smaller than minimum"))
+		}
+	}
+
+	if d, ok := attributes["decimal"]; ok {
+		l := attributeLines["decimal"]
+		decimal, err := parseBool(d)
+		if err != nil {
+			errs = append(errs, l.errorAt(l.valueOffset(), "invalid value for decimal: %s", err.Error()))
+		}
+		if decimal {
+			data["d"] = "true"
+		}
+	}
+	if c, ok := values["correct"]; ok && c != float64(int64(c)) {
+		data["d"] = "true"
+	}
+	errs = append(errs, timeLimit(data, attributes, attributeLines)...)
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		errs = append(errs, s.heading.errorAt(0, "%s", err.Error()))
+	}
+	tl := translation.GetDefaultTranslation()
+	return Item{From: "Number", Data: string(b), Description: describe(tl.DisplayNumber, question)}, errs
+}
+
+// text returns a TextDisplay item. The text is rendered through helper.Format to ensure that something is displayed.
+func (s *section) text(text []line) (Item, []Error) {
+	for len(text) != 0 && strings.TrimSpace(text[len(text)-1].text) == "" {
+		text = text[:len(text)-1]
+	}
+	parts := make([]string, 0, len(text)+2)
+	if s.title != "" {
+		parts = append(parts, "# "+s.title, "")
+	}
+	for i := range text {
+		parts = append(parts, text[i].text)
+	}
+	markdown := strings.Join(parts, "\n")
+
+	if strings.TrimSpace(string(helper.Format([]byte(markdown)))) == "" {
+		return Item{}, []Error{s.heading.errorAt(0, "text is empty")}
+	}
+	tl := translation.GetDefaultTranslation()
+	firstLine := strings.SplitN(strings.TrimSpace(markdown), "\n", 2)[0]
+	return Item{From: "TextDisplay", Data: markdown, Description: describe(tl.DisplayText, firstLine)}, nil
+}
+
+// element returns an item for an arbitrary plugin. The plugin name is the title of the section.
+func (s *section) element(text []line, code []string, codeFound bool) (Item, []Error) {
+	errs := make([]Error, 0)
+	name := s.title
+	if name == "" {
+		return Item{}, []Error{s.heading.errorAt(len(s.heading.text), "no plugin name found")}
+	}
+	if _, ok := registry.GetFeedbackPlugins(name); !ok {
+		errs = append(errs, Error{Line: s.heading.number, Column: s.titleCol, Message: fmt.Sprintf("unknown plugin %s", name)})
+	}
+	if len(text) != 0 {
+		errs = append(errs, text[0].errorAt(0, "text outside of code block"))
+	}
+	if !codeFound {
+		errs = append(errs, s.heading.errorAt(0, "no code block found"))
+	}
+	return Item{From: name, Data: strings.Join(code, "\n"), Description: name}, errs
+}
+
+// timeLimit adds the time limit to the data of an element.
+func timeLimit(data, attributes map[string]string, attributeLines map[string]line) []Error {
+	v, ok := attributes["time-limit"]
+	if !ok {
+		return nil
+	}
+	l := attributeLines["time-limit"]
+	d, err := parseDuration(v)
+	if err != nil {
+		return []Error{l.errorAt(l.valueOffset(), "invalid time limit: %s", err.Error())}
+	}
+	data["tl"] = strconv.Itoa(d)
+	return nil
+}
+
+// parseDuration parses a number of seconds or a duration like "2m30s" and returns the number of seconds.
+func parseDuration(s string) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err == nil {
+		if i < 0 || i > int(maxDuration/time.Second) {
+			return 0, fmt.Errorf("must be between 0 and %d seconds", int(maxDuration/time.Second))
+		}
+		return i, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s is neither a number of seconds nor a duration", s)
+	}
+	if d < 0 || d > maxDuration {
+		return 0, fmt.Errorf("must be between 0 and %s", maxDuration.String())
+	}
+	if d%time.Second != 0 {
+		return 0, fmt.Errorf("must be a whole number of seconds")
+	}
+	return int(d / time.Second), nil
+}
+
+// parseBool parses a boolean value. An empty value is interpreted as true.
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "", "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("%s is not a boolean", s)
+}
+
+// describe returns a description for an element in the same style as the admin page.
+func describe(name, text string) string {
+	r := []rune(text)
+	if len(r) > maxDescriptionLength {
+		text = string(r[:maxDescriptionLength]) + "[...]"
+	}
+	return fmt.Sprintf("%s: %s", name, text)
+}
@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020,2021,2023 Marcus Soll
+// Copyright 2020,2021,2023,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"html/template"
 	"log"
+	"time"
 )
 
 // ChartValue represents a single data point in a chart.
@@ -164,3 +165,206 @@ func BarChart(v []ChartValue, id, label string) template.HTML {
 	}
 	return template.HTML(output.Bytes())
 }
+
+// ChartSeries represents a single data set of a chart with multiple data sets.
+type ChartSeries struct {
+	Label  string
+	Colour string
+	Values []float64
+}
+
+var stackedChartTemplate = template.Must(template.New("stackedChartTemplate").Parse(`
+<div class="chart barchart">
+	<canvas id="{{.ID}}"></canvas>
+</div>
+<script>
+var ctx = document.getElementById('{{.ID}}').getContext('2d');
+var chartData = {
+	type: "bar",
+	data: {
+		datasets: [
+			{{range $i, $e := .Series}}
+			{
+				data: [
+					{{range $j, $v := $e.Values}}
+					{{$v}},
+					{{end}}
+				],
+				backgroundColor: {{$e.Colour}},
+				label: {{$e.Label}}
+			},
+			{{end}}
+		],
+		labels: [
+			{{range $i, $e := .Labels}}
+			{{$e}},
+			{{end}}
+		],
+	},
+	options: {
+		indexAxis: 'y',
+		plugins: {
+			title: {
+				display: true,
+				text: {{.Label}}
+			}
+		},
+		responsive: true,
+		scales: {
+			x: {
+				stacked: true,
+				beginAtZero: true,
+				{{if .Max}}max: {{.Max}},{{end}}
+			},
+			y: {
+				stacked: true
+			}
+		},
+	}
+};
+var chart = new Chart(ctx, chartData);
+</script>
+`))
+
+type stackedChartTemplateStruct struct {
+	Series []ChartSeries
+	Labels []string
+	ID     string
+	Label  string
+	Max    float64
+}
+
+// StackedBarChart returns a save HTML fragment of the data as a horizontal stacked bar chart.
+// Each series forms one segment of every bar, labels contains the label of each bar.
+// If max is positive, it is used as the maximum of the value axis (e.g. 100 for percentages).
+// If the colour of a series is empty, a colour is chosen automatically.
+// User must embed chart.js.
+func StackedBarChart(series []ChartSeries, labels []string, id, label string, max float64) template.HTML {
+	colours := getColours(len(series))
+	s := make([]ChartSeries, len(series))
+	copy(s, series)
+	for i := range s {
+		if s[i].Colour == "" {
+			s[i].Colour = colours[i]
+		}
+	}
+
+	td := stackedChartTemplateStruct{
+		Series: s,
+		Labels: labels,
+		ID:     id,
+		Label:  label,
+		Max:    max,
+	}
+	output := bytes.NewBuffer(make([]byte, 0))
+	err := stackedChartTemplate.Execute(output, td)
+	if err != nil {
+		log.Printf("stacked bar chart: Error executing template (%s)", err.Error())
+	}
+	return template.HTML(output.Bytes())
+}
+
+// TimeValue represents a single data point on a time axis.
+type TimeValue struct {
+	Time  time.Time
+	Value float64
+}
+
+var timeChartTemplate = template.Must(template.New("timeChartTemplate").Parse(`
+<div class="chart barchart">
+	<canvas id="{{.ID}}"></canvas>
+</div>
+<script>
+var ctx = document.getElementById('{{.ID}}').getContext('2d');
+var chartData = {
+	type: "bar",
+	data: {
+		datasets: [{
+			data: [
+				{{range $i, $e := .Data}}
+				{x: {{$e.X}}, y: {{$e.Y}}},
+				{{end}}
+			],
+			backgroundColor: {{.Colour}},
+			barPercentage: 1.0,
+			categoryPercentage: 1.0,
+			label: {{.Label}}
+		}],
+	},
+	options: {
+		plugins: {
+			title: {
+				display: true,
+				text: {{.Label}}
+			}
+		},
+		responsive: true,
+		scales: {
+			x: {
+				type: 'time',
+				time: {
+					tooltipFormat: {{.Format}},
+					displayFormats: {
+						minute: 'HH:mm',
+						hour: {{.HourFormat}},
+					}
+				}
+			},
+			y: {
+				beginAtZero: true,
+				ticks: {
+					precision: 0
+				}
+			}
+		},
+	}
+};
+var chart = new Chart(ctx, chartData);
+</script>
+`))
+
+type timeChartPoint struct {
+	X string
+	Y float64
+}
+
+type timeChartTemplateStruct struct {
+	Data       []timeChartPoint
+	Colour     string
+	ID         string
+	Label      string
+	Format     string
+	HourFormat string
+}
+
+// timeChartLayout is the layout used to transfer times to the chart. Times are shown as they are, regardless of the time zone of the browser.
+const timeChartLayout = "2006-01-02T15:04:05"
+
+// TimeHistogram returns a save HTML fragment of the data as a bar chart on a continuous time axis.
+// Each value is shown as a bar of the given width starting at its time.
+// If timeOfDay is true, only the time of the day is shown.
+// User must embed chart.js, moment.js and chartjs-adapter-moment.
+func TimeHistogram(v []TimeValue, width time.Duration, id, label string, timeOfDay bool) template.HTML {
+	td := timeChartTemplateStruct{
+		Data:       make([]timeChartPoint, len(v)),
+		Colour:     getColours(1)[0],
+		ID:         id,
+		Label:      label,
+		Format:     "YYYY-MM-DD HH:mm",
+		HourFormat: "MM-DD HH:mm",
+	}
+	for i := range v {
+		td.Data[i] = timeChartPoint{X: v[i].Time.Add(width / 2).Format(timeChartLayout), Y: v[i].Value}
+	}
+	if timeOfDay {
+		td.Format = "HH:mm"
+		td.HourFormat = "HH:mm"
+	}
+
+	output := bytes.NewBuffer(make([]byte, 0))
+	err := timeChartTemplate.Execute(output, td)
+	if err != nil {
+		log.Printf("time histogram: Error executing template (%s)", err.Error())
+	}
+	return template.HTML(output.Bytes())
+}
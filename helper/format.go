@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020 Marcus Soll
+// Copyright 2020,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+	"regexp"
 
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/yuin/goldmark"
@@ -27,26 +28,61 @@ import (
 )
 
 var policy *bluemonday.Policy
+var imagePolicy *bluemonday.Policy
+
+// internalImageRegexp matches the URL of images uploaded to a response.
+// These URLs are relative to the response page.
+var internalImageRegexp = regexp.MustCompile(`^\?image=[A-Z2-7=]+$`)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("a", "b", "blockquote", "br", "caption", "code", "del", "em", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "ins", "kbd", "mark", "p", "pre", "q", "s", "samp", "strong", "sub", "sup", "u")
+	p.AllowLists()
+	p.AllowStandardURLs()
+	p.AllowAttrs("href").OnElements("a")
+	p.RequireNoReferrerOnLinks(true)
+	p.AllowTables()
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
 
 func init() {
-	policy = bluemonday.NewPolicy()
-	policy.AllowElements("a", "b", "blockquote", "br", "caption", "code", "del", "em", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "ins", "kbd", "mark", "p", "pre", "q", "s", "samp", "strong", "sub", "sup", "u")
-	policy.AllowLists()
-	policy.AllowStandardURLs()
-	policy.AllowAttrs("href").OnElements("a")
-	policy.RequireNoReferrerOnLinks(true)
-	policy.AllowTables()
-	policy.AddTargetBlankToFullyQualifiedLinks(true)
+	policy = newPolicy()
+
+	imagePolicy = newPolicy()
+	imagePolicy.AllowAttrs("src").Matching(internalImageRegexp).OnElements("img")
+	imagePolicy.AllowAttrs("alt").OnElements("img")
+}
+
+// InternalImageURL returns the URL of an image uploaded to a response.
+// The URL is relative to the response page.
+func InternalImageURL(id string) string {
+	return fmt.Sprintf("?image=%s", id)
+}
+
+// IsInternalImageURL returns whether the URL points to an image uploaded to a response.
+func IsInternalImageURL(url string) bool {
+	return internalImageRegexp.MatchString(url)
 }
 
 // Format returns a save html version of the Markdown input.
 func Format(b []byte) template.HTML {
+	return format(b, policy)
+}
+
+// FormatWithInternalImages returns a save html version of the Markdown input.
+// In contrast to Format, images uploaded to the response are allowed. All other images are still removed.
+func FormatWithInternalImages(b []byte) template.HTML {
+	return format(b, imagePolicy)
+}
+
+func format(b []byte, p *bluemonday.Policy) template.HTML {
 	buf := bytes.NewBuffer(make([]byte, 0, len(b)*2))
 	md := goldmark.New(goldmark.WithExtensions(extension.GFM), goldmark.WithRendererOptions(html.WithHardWraps()))
 	err := md.Convert(b, buf)
 	if err != nil {
-		return template.HTML(policy.Sanitize(fmt.Sprintf("Error rendering markdown: %s", err.Error())))
+		return template.HTML(p.Sanitize(fmt.Sprintf("Error rendering markdown: %s", err.Error())))
 	}
 
-	return template.HTML(policy.SanitizeBytes(buf.Bytes()))
+	return template.HTML(p.SanitizeBytes(buf.Bytes()))
 }
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Statistics contains summary statistics of a sample.
+type Statistics struct {
+	N                 int
+	Mean              float64
+	Median            float64
+	StandardDeviation float64
+	Min               float64
+	Max               float64
+	P10               float64
+	P25               float64
+	P75               float64
+	P90               float64
+}
+
+// CalculateStatistics returns the summary statistics of v.
+// v is not modified. The standard deviation is the sample standard deviation.
+// If v is empty, the zero value is returned.
+func CalculateStatistics(v []float64) Statistics {
+	if len(v) == 0 {
+		return Statistics{}
+	}
+
+	s := make([]float64, len(v))
+	copy(s, v)
+	sort.Float64s(s)
+
+	sum := 0.0
+	for i := range s {
+		sum += s[i]
+	}
+	mean := sum / float64(len(s))
+
+	sd := 0.0
+	if len(s) > 1 {
+		for i := range s {
+			sd += (s[i] - mean) * (s[i] - mean)
+		}
+		sd = math.Sqrt(sd / float64(len(s)-1))
+	}
+
+	return Statistics{
+		N:                 len(s),
+		Mean:              mean,
+		Median:            Percentile(s, 50),
+		StandardDeviation: sd,
+		Min:               s[0],
+		Max:               s[len(s)-1],
+		P10:               Percentile(s, 10),
+		P25:               Percentile(s, 25),
+		P75:               Percentile(s, 75),
+		P90:               Percentile(s, 90),
+	}
+}
+
+// Percentile returns the p-th percentile (0 <= p <= 100) of an already sorted slice using linear interpolation.
+// If sorted is empty, 0 is returned.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if p <= 0 {
+		return sorted[0]
+	}
+	if p >= 100 {
+		return sorted[len(sorted)-1]
+	}
+	pos := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+// TrimOutliers removes the given percentage (0 <= percent < 50) of values from both ends of the sample.
+// The returned slice is sorted. v is not modified.
+func TrimOutliers(v []float64, percent float64) []float64 {
+	s := make([]float64, len(v))
+	copy(s, v)
+	sort.Float64s(s)
+
+	if percent <= 0 || len(s) == 0 {
+		return s
+	}
+	if percent >= 50 {
+		percent = 49.999
+	}
+
+	n := int(math.Floor(float64(len(s)) * percent / 100))
+	return s[n : len(s)-n]
+}
+
+// Histogram sorts v into the given number of bins of equal width and returns them as chart values.
+// If integer is true and the range of v is covered by at most bins integers, every integer gets its own bin.
+func Histogram(v []float64, bins int, integer bool) []ChartValue {
+	if len(v) == 0 || bins <= 0 {
+		return nil
+	}
+
+	min, max := v[0], v[0]
+	for i := range v {
+		min = math.Min(min, v[i])
+		max = math.Max(max, v[i])
+	}
+
+	if integer && max-min+1 <= float64(bins) {
+		c := make([]ChartValue, int(max-min)+1)
+		for i := range c {
+			c[i].Label = fmt.Sprintf("%d", int(min)+i)
+		}
+		for i := range v {
+			c[int(v[i]-min)].Value++
+		}
+		return c
+	}
+
+	if min == max {
+		return []ChartValue{{Label: FormatFloat(min), Value: float64(len(v))}}
+	}
+
+	width := (max - min) / float64(bins)
+	c := make([]ChartValue, bins)
+	for i := range c {
+		c[i].Label = fmt.Sprintf("%s – %s", FormatFloat(min+float64(i)*width), FormatFloat(min+float64(i+1)*width))
+	}
+	for i := range v {
+		b := int((v[i] - min) / width)
+		if b >= bins {
+			// The maximum belongs to the last bin
+			b = bins - 1
+		}
+		c[b].Value++
+	}
+	return c
+}
+
+// FormatFloat returns a short human readable representation of f rounded to two decimal places.
+func FormatFloat(f float64) string {
+	return strconv.FormatFloat(RoundFloat(f, 2), 'f', -1, 64)
+}
+
+// RoundFloat rounds f to the given number of decimal places.
+func RoundFloat(f float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(f*p) / p
+}
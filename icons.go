@@ -0,0 +1,293 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Top-Ranger/responsego/helper"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+const (
+	iconPresetLecture  = "lecture"
+	iconPresetWorkshop = "workshop"
+	iconPresetYesNo    = "yesno"
+	defaultIconPreset  = iconPresetLecture
+)
+
+const (
+	maxIcons           = 8
+	maxIconLabelLength = 50
+)
+
+const (
+	iconModeTotal  = "total"
+	iconModeWindow = "window"
+	iconModeDecay  = "decay"
+)
+
+const (
+	defaultIconModeMinutes = 5
+	maxIconModeMinutes     = 24 * 60
+	maxIconEvents          = 100000
+	iconTimelineInterval   = time.Minute
+)
+
+// staticIcons contains all icons in static/ which can be used for quick feedback.
+var staticIcons = []string{"slower", "break", "faster", "question", "good", "neutral", "bad", "yes", "no"}
+
+// icon represents a single quick feedback icon participants can click on.
+// Image is the URL of the image, either in static/ or uploaded to the response.
+type icon struct {
+	Name  string
+	Label string
+	Image string
+}
+
+// staticIconURL returns the URL of an icon in static/.
+func staticIconURL(name string) string {
+	return strings.Join([]string{config.ServerPath, "/static/", name, ".svg"}, "")
+}
+
+// isStaticIconURL returns whether url points to one of the staticIcons.
+func isStaticIconURL(url string) bool {
+	for i := range staticIcons {
+		if url == staticIconURL(staticIcons[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// iconPresetOption represents a preset which can be chosen by the presenter.
+type iconPresetOption struct {
+	Name  string
+	Label string
+}
+
+// iconPresetOptions returns all presets in the order they should be presented.
+func iconPresetOptions(tl translation.Translation) []iconPresetOption {
+	return []iconPresetOption{
+		{iconPresetLecture, tl.IconPresetLecture},
+		{iconPresetWorkshop, tl.IconPresetWorkshop},
+		{iconPresetYesNo, tl.IconPresetYesNo},
+	}
+}
+
+// getIconPreset returns the icons of a preset. The second return value is false if the preset does not exist.
+func getIconPreset(preset string) ([]icon, bool) {
+	tl := translation.GetDefaultTranslation()
+	i := func(name, label string) icon {
+		return icon{Name: name, Label: label, Image: staticIconURL(name)}
+	}
+
+	switch preset {
+	case iconPresetLecture:
+		return []icon{i("slower", tl.Slower), i("break", tl.Break), i("faster", tl.Faster), i("question", tl.Question), i("good", tl.Good)}, true
+	case iconPresetWorkshop:
+		return []icon{i("good", tl.Good), i("neutral", tl.Neutral), i("bad", tl.Bad), i("question", tl.Question), i("break", tl.Break)}, true
+	case iconPresetYesNo:
+		return []icon{i("yes", tl.Yes), i("no", tl.No), i("question", tl.Unsure)}, true
+	}
+	return nil, false
+}
+
+// validateIcons checks a custom icon set and assigns the names of the icons.
+func validateIcons(icons []icon) error {
+	if len(icons) == 0 {
+		return errors.New("no icons")
+	}
+	if len(icons) > maxIcons {
+		return fmt.Errorf("at most %d icons allowed", maxIcons)
+	}
+
+	for i := range icons {
+		icons[i].Label = strings.TrimSpace(icons[i].Label)
+		if icons[i].Label == "" {
+			return fmt.Errorf("icon %d has no label", i+1)
+		}
+		if len([]rune(icons[i].Label)) > maxIconLabelLength {
+			return fmt.Errorf("label of icon %d is too long", i+1)
+		}
+		if !isStaticIconURL(icons[i].Image) && !helper.IsInternalImageURL(icons[i].Image) {
+			return fmt.Errorf("icon %d has an invalid image", i+1)
+		}
+		icons[i].Name = fmt.Sprintf("icon%d", i+1)
+	}
+	return nil
+}
+
+// iconThreshold is the value of a counter at which the presenter is alerted.
+// If Percent is true, Value is a percentage of the connected participants.
+type iconThreshold struct {
+	Value   float64
+	Percent bool
+	Log     bool
+}
+
+// limit returns the counter value at which the threshold is reached.
+// The second return value is false if the threshold can not be reached (e.g. no participants are connected).
+func (t iconThreshold) limit(participants int) (float64, bool) {
+	if t.Value <= 0 {
+		return 0, false
+	}
+	if !t.Percent {
+		return t.Value, true
+	}
+	if participants == 0 {
+		return 0, false
+	}
+	return t.Value / 100 * float64(participants), true
+}
+
+// iconAlert is send to the presenter when a threshold is reached.
+type iconAlert struct {
+	Name      string
+	Label     string
+	Count     float64
+	Threshold float64
+}
+
+// iconEvent represents a single click on an icon.
+// The label is stored so that events stay meaningful after the icon set is changed.
+type iconEvent struct {
+	Time  time.Time
+	Name  string
+	Label string
+}
+
+// iconCounterMode describes how the counter of an icon is calculated.
+// For iconModeWindow, Minutes is the length of the sliding window.
+// For iconModeDecay, Minutes is the half-life of a click.
+type iconCounterMode struct {
+	Mode    string
+	Minutes float64
+}
+
+// validate checks the mode and sets the default length if none is given.
+func (m *iconCounterMode) validate() error {
+	switch m.Mode {
+	case "", iconModeTotal:
+		m.Mode = iconModeTotal
+		m.Minutes = 0
+		return nil
+	case iconModeWindow, iconModeDecay:
+	default:
+		return fmt.Errorf("unknown counter mode %s", m.Mode)
+	}
+	if m.Minutes == 0 {
+		m.Minutes = defaultIconModeMinutes
+	}
+	if m.Minutes < 0 || m.Minutes > maxIconModeMinutes || math.IsNaN(m.Minutes) {
+		return fmt.Errorf("invalid length %f", m.Minutes)
+	}
+	return nil
+}
+
+// isTotal returns whether the counter simply counts all clicks since the last reset.
+func (m iconCounterMode) isTotal() bool {
+	return m.Mode == "" || m.Mode == iconModeTotal
+}
+
+// iconValue calculates the current value of an icon counter from the event log.
+// Only events after since are considered. Events must be sorted by time.
+func iconValue(events []iconEvent, name string, since time.Time, mode iconCounterMode, now time.Time) float64 {
+	length := time.Duration(mode.Minutes * float64(time.Minute))
+	value := 0.0
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Time.Before(since) {
+			break
+		}
+		age := now.Sub(events[i].Time)
+		switch mode.Mode {
+		case iconModeWindow:
+			if age > length {
+				return value
+			}
+			if events[i].Name == name {
+				value++
+			}
+		case iconModeDecay:
+			// Older events contribute less than 0.1% and can be ignored
+			if age > 10*length {
+				return value
+			}
+			if events[i].Name == name {
+				value += math.Pow(0.5, float64(age)/float64(length))
+			}
+		default:
+			if events[i].Name == name {
+				value++
+			}
+		}
+	}
+	return value
+}
+
+// iconTimeline is the activity of all icons over time.
+// Counts contains for each bucket the number of clicks for each label.
+type iconTimeline struct {
+	Labels  []string
+	Buckets []iconTimelineBucket
+}
+
+type iconTimelineBucket struct {
+	Time   string
+	Counts []int
+}
+
+// getIconTimeline groups the events into buckets of iconTimelineInterval. Events must be sorted by time.
+// Labels are sorted by their first appearance. Empty buckets are omitted.
+func getIconTimeline(events []iconEvent) iconTimeline {
+	t := iconTimeline{Labels: make([]string, 0), Buckets: make([]iconTimelineBucket, 0)}
+	labels := make(map[string]int)
+	for i := range events {
+		if _, ok := labels[events[i].Label]; !ok {
+			labels[events[i].Label] = len(t.Labels)
+			t.Labels = append(t.Labels, events[i].Label)
+		}
+	}
+
+	var current time.Time
+	for i := range events {
+		start := events[i].Time.Truncate(iconTimelineInterval)
+		if len(t.Buckets) == 0 || !start.Equal(current) {
+			current = start
+			t.Buckets = append(t.Buckets, iconTimelineBucket{Time: start.Local().Format("2006-01-02T15:04:05"), Counts: make([]int, len(t.Labels))})
+		}
+		t.Buckets[len(t.Buckets)-1].Counts[labels[events[i].Label]]++
+	}
+	return t
+}
+
+// iconEventsCSV returns the raw event series as CSV.
+func iconEventsCSV(events []iconEvent) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"time", "icon", "label"})
+	for i := range events {
+		w.Write([]string{events[i].Time.Format(time.RFC3339Nano), events[i].Name, events[i].Label})
+	}
+	w.Flush()
+	return buf.Bytes()
+}
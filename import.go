@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/Top-Ranger/responsego/importer"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+const maxImportSizeKB = 5 * 1024
+
+// importResult is returned to the admin page after importing a file.
+type importResult struct {
+	Elements []importer.Element
+	Problems []string
+}
+
+// HandleImport converts a GIFT or Aiken file send as request body into saved elements.
+// The format can be given through the 'format' parameter, otherwise it is detected.
+func (r *response) HandleImport(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	b, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxImportSizeKB*1024))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	elements, problems, err := importer.Import(b, req.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := importResult{Elements: elements, Problems: make([]string, len(problems))}
+	for i := range problems {
+		result.Problems[i] = problems[i].String()
+	}
+	b, err = json.Marshal(result)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(b)
+}
+
+// importCommand implements the 'import' subcommand.
+// It converts GIFT or Aiken files into the saved elements format of the admin page and returns the exit code.
+func importCommand(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", "", "Format of the input (gift, aiken). If empty, the format is detected.")
+	language := fs.String("language", "en", "Language used for the descriptions of the elements")
+	output := fs.String("output", "", "Path of the output file. If empty, the elements are written to stdout.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s import [options] file...\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Converts Moodle GIFT or Aiken files into saved elements of ResponseGo!. If no file is given, stdin is read.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	logger := log.New(os.Stderr, "", 0)
+	err := translation.SetDefaultTranslation(*language)
+	if err != nil {
+		logger.Printf("import: can not set language %s: %s", *language, err.Error())
+		return 2
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	elements := make([]importer.Element, 0)
+	for _, f := range files {
+		var b []byte
+		if f == "-" {
+			b, err = io.ReadAll(os.Stdin)
+		} else {
+			b, err = os.ReadFile(f)
+		}
+		if err != nil {
+			logger.Printf("import: can not read %s: %s", f, err.Error())
+			return 1
+		}
+		e, problems, err := importer.Import(b, *format)
+		if err != nil {
+			logger.Printf("import: %s", err.Error())
+			return 2
+		}
+		for _, p := range problems {
+			logger.Printf("%s: %s", f, p.String())
+		}
+		elements = append(elements, e...)
+	}
+
+	b, err := json.Marshal(elements)
+	if err != nil {
+		logger.Printf("import: %s", err.Error())
+		return 1
+	}
+	if *output == "" {
+		os.Stdout.Write(b)
+		os.Stdout.Write([]byte("\n"))
+		return 0
+	}
+	err = os.WriteFile(*output, b, 0644)
+	if err != nil {
+		logger.Printf("import: can not write %s: %s", *output, err.Error())
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	aikenOptionRegexp = regexp.MustCompile(`^([A-Za-z])[.)]\s+(.*)$`)
+	aikenAnswerLine   = regexp.MustCompile(`^ANSWER:\s*([A-Za-z])\s*$`)
+)
+
+// aikenQuestion is a question of an Aiken file which is currently parsed.
+type aikenQuestion struct {
+	line     int
+	question []string
+	options  []string
+}
+
+// ParseAiken converts all questions in the Aiken format.
+// Each question consists of the question text, options starting with a letter ("A." or "A)") and a line "ANSWER: <letter>".
+// All questions are converted into Question elements.
+func ParseAiken(b []byte) ([]Element, []Problem) {
+	s := strings.ReplaceAll(string(b), "\r\n", "\n")
+	s = strings.TrimPrefix(s, "\ufeff")
+
+	elements := make([]Element, 0)
+	problems := make([]Problem, 0)
+	var current *aikenQuestion
+
+	for i, l := range strings.Split(s, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+
+		if m := aikenAnswerLine.FindStringSubmatch(l); m != nil {
+			if current == nil {
+				problems = append(problems, Problem{Line: i + 1, Message: "answer without question"})
+				continue
+			}
+			e, err := current.element(m[1])
+			if err != nil {
+				problems = append(problems, Problem{Line: current.line, Message: fmt.Sprintf("question skipped: %s", err.Error())})
+			} else {
+				elements = append(elements, e)
+			}
+			current = nil
+			continue
+		}
+
+		if m := aikenOptionRegexp.FindStringSubmatch(l); m != nil && current != nil && len(current.question) != 0 {
+			expected := 'A' + rune(len(current.options))
+			if strings.ToUpper(m[1]) == string(expected) {
+				current.options = append(current.options, strings.TrimSpace(m[2]))
+				continue
+			}
+		}
+
+		if current != nil && len(current.options) != 0 {
+			// A new question starts before the answer of the last question
+			problems = append(problems, Problem{Line: current.line, Message: "question skipped: no ANSWER line found"})
+			current = nil
+		}
+		if current == nil {
+			current = &aikenQuestion{line: i + 1}
+		}
+		current.question = append(current.question, l)
+	}
+	if current != nil {
+		problems = append(problems, Problem{Line: current.line, Message: "question skipped: no ANSWER line found"})
+	}
+	return elements, problems
+}
+
+// element converts the question. answer is the letter of the correct answer.
+func (q *aikenQuestion) element(answer string) (Element, error) {
+	if len(q.options) == 0 {
+		return Element{}, fmt.Errorf("no options found")
+	}
+	if i := int(strings.ToUpper(answer)[0] - 'A'); i >= len(q.options) {
+		return Element{}, fmt.Errorf("answer %s does not exist", answer)
+	}
+	return choiceElement(strings.Join(q.question, "\n"), q.options, false)
+}
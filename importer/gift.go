@@ -0,0 +1,383 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+// giftMissingWord replaces the answer block of questions with text after the answers.
+const giftMissingWord = "_____"
+
+var giftFormatRegexp = regexp.MustCompile(`^\[(html|moodle|plain|markdown)\]`)
+
+// giftBlock is a single question of a GIFT file. line is the first line of the block.
+type giftBlock struct {
+	line int
+	text string
+}
+
+// giftAnswer is a single answer of a choice question.
+// correct is true for answers starting with '='. weight is the percentage given through '%weight%', if any.
+type giftAnswer struct {
+	text      string
+	correct   bool
+	weight    float64
+	hasWeight bool
+}
+
+// ParseGIFT converts all questions in the Moodle GIFT format.
+// Supported are multiple choice (single and multiple correct answers), true/false, short answer, numerical and essay questions as well as descriptions.
+// Categories are used as folder.
+func ParseGIFT(b []byte) ([]Element, []Problem) {
+	elements := make([]Element, 0)
+	problems := make([]Problem, 0)
+	folder := ""
+
+	for _, block := range splitGIFT(string(b)) {
+		if strings.HasPrefix(block.text, "$CATEGORY:") {
+			lines := strings.SplitN(block.text, "\n", 2)
+			folder = strings.TrimSpace(strings.TrimPrefix(lines[0], "$CATEGORY:"))
+			folder = strings.TrimPrefix(folder, "$course$/")
+			if len(lines) == 1 {
+				continue
+			}
+			block.text = strings.TrimSpace(lines[1])
+			block.line++
+		}
+
+		e, notes, err := parseGIFTQuestion(block.text)
+		for _, n := range notes {
+			problems = append(problems, Problem{Line: block.line, Message: n})
+		}
+		if err != nil {
+			problems = append(problems, Problem{Line: block.line, Message: fmt.Sprintf("question skipped: %s", err.Error())})
+			continue
+		}
+		e.Folder = folder
+		elements = append(elements, e)
+	}
+	return elements, problems
+}
+
+// splitGIFT splits the input into questions. Questions are separated by empty lines, comments are removed.
+// Empty lines inside of an answer block do not end a question.
+func splitGIFT(s string) []giftBlock {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimPrefix(s, "\ufeff")
+	lines := strings.Split(s, "\n")
+
+	blocks := make([]giftBlock, 0)
+	var current []string
+	start := 0
+	open := 0
+	for i, l := range lines {
+		trimmed := strings.TrimSpace(l)
+		if strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		if trimmed == "" {
+			if len(current) != 0 && open <= 0 {
+				blocks = append(blocks, giftBlock{line: start, text: strings.TrimSpace(strings.Join(current, "\n"))})
+				current = nil
+				open = 0
+			}
+			continue
+		}
+		if len(current) == 0 {
+			start = i + 1
+		}
+		current = append(current, l)
+		open += countUnescaped(l, '{') - countUnescaped(l, '}')
+	}
+	if len(current) != 0 {
+		blocks = append(blocks, giftBlock{line: start, text: strings.TrimSpace(strings.Join(current, "\n"))})
+	}
+	return blocks
+}
+
+// parseGIFTQuestion converts a single question. notes contains changes made to the question during conversion.
+func parseGIFTQuestion(text string) (Element, []string, error) {
+	var notes []string
+
+	title := ""
+	if strings.HasPrefix(text, "::") {
+		end := indexUnescaped(text[2:], "::")
+		if end == -1 {
+			return Element{}, notes, errors.New("title is not terminated")
+		}
+		title = strings.TrimSpace(unescapeGIFT(text[2 : 2+end]))
+		text = strings.TrimSpace(text[2+end+2:])
+	}
+	if f := giftFormatRegexp.FindStringSubmatch(text); f != nil {
+		text = strings.TrimSpace(text[len(f[0]):])
+		if f[1] == "html" {
+			notes = append(notes, "HTML formatting is imported as plain text")
+		}
+	}
+
+	open := indexUnescaped(text, "{")
+	if open == -1 {
+		// Description
+		text = unescapeGIFT(text)
+		if text == "" {
+			text = title
+		}
+		if text == "" {
+			return Element{}, notes, errors.New("empty question")
+		}
+		return textElement(text), notes, nil
+	}
+	end := indexUnescaped(text[open:], "}")
+	if end == -1 {
+		return Element{}, notes, errors.New("answer block is not terminated")
+	}
+	end += open
+	before, answers, after := strings.TrimSpace(text[:open]), strings.TrimSpace(text[open+1:end]), strings.TrimSpace(text[end+1:])
+	if indexUnescaped(after, "{") != -1 {
+		return Element{}, notes, errors.New("multiple answer blocks are not supported")
+	}
+
+	question := before
+	if after != "" {
+		question = strings.TrimSpace(strings.Join([]string{before, giftMissingWord, after}, " "))
+	}
+	question = unescapeGIFT(question)
+	if question == "" {
+		question = title
+	}
+	if question == "" {
+		return Element{}, notes, errors.New("empty question")
+	}
+
+	if i := indexUnescaped(answers, "####"); i != -1 {
+		answers = strings.TrimSpace(answers[:i])
+	}
+
+	switch {
+	case answers == "":
+		// Essay
+		return freeTextElement(question), notes, nil
+	case strings.HasPrefix(answers, "#"):
+		correct, numericalNotes, err := parseGIFTNumerical(answers[1:])
+		if err != nil {
+			return Element{}, notes, err
+		}
+		notes = append(notes, numericalNotes...)
+		e, err := numberElement(question, correct)
+		return e, notes, err
+	}
+
+	tf := answers
+	if i := indexUnescaped(tf, "#"); i != -1 {
+		tf = tf[:i]
+	}
+	switch strings.ToUpper(strings.TrimSpace(tf)) {
+	case "T", "TRUE", "F", "FALSE":
+		tl := translation.GetDefaultTranslation()
+		e, err := choiceElement(question, []string{tl.True, tl.False}, false)
+		return e, notes, err
+	}
+
+	if indexUnescaped(answers, "->") != -1 {
+		return Element{}, notes, errors.New("matching questions are not supported")
+	}
+	parsed, err := parseGIFTAnswers(answers)
+	if err != nil {
+		return Element{}, notes, err
+	}
+
+	shortAnswer := true
+	correct := 0
+	texts := make([]string, len(parsed))
+	for i := range parsed {
+		texts[i] = parsed[i].text
+		if !parsed[i].correct {
+			shortAnswer = false
+		}
+		if parsed[i].correct || (parsed[i].hasWeight && parsed[i].weight > 0) {
+			correct++
+		}
+	}
+	if shortAnswer {
+		return freeTextElement(question), notes, nil
+	}
+	e, err := choiceElement(question, texts, correct > 1)
+	return e, notes, err
+}
+
+// parseGIFTAnswers splits the answers of a choice question. Feedback is removed.
+func parseGIFTAnswers(s string) ([]giftAnswer, error) {
+	var raw []string
+	var kinds []rune
+	escaped := false
+	start := -1
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '=' || r == '~':
+			if start == -1 && strings.TrimSpace(s[:i]) != "" {
+				return nil, fmt.Errorf("text outside of answers: %s", strings.TrimSpace(s[:i]))
+			}
+			if start != -1 {
+				raw = append(raw, s[start:i])
+			}
+			kinds = append(kinds, r)
+			start = i + 1
+		}
+	}
+	if start == -1 {
+		return nil, fmt.Errorf("unknown answer format: %s", s)
+	}
+	raw = append(raw, s[start:])
+
+	answers := make([]giftAnswer, len(raw))
+	for i := range raw {
+		a := raw[i]
+		if j := indexUnescaped(a, "#"); j != -1 {
+			a = a[:j]
+		}
+		a = strings.TrimSpace(a)
+		answers[i].correct = kinds[i] == '='
+		if strings.HasPrefix(a, "%") {
+			end := strings.Index(a[1:], "%")
+			if end == -1 {
+				return nil, fmt.Errorf("weight of answer %d is not terminated", i+1)
+			}
+			w, err := strconv.ParseFloat(a[1:1+end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("can not parse weight of answer %d: %w", i+1, err)
+			}
+			answers[i].weight = w
+			answers[i].hasWeight = true
+			a = strings.TrimSpace(a[end+2:])
+		}
+		answers[i].text = unescapeGIFT(a)
+		if answers[i].text == "" {
+			return nil, fmt.Errorf("answer %d is empty", i+1)
+		}
+	}
+	return answers, nil
+}
+
+// parseGIFTNumerical returns the correct value of a numerical question.
+// If multiple answers are given, the first answer with full weight is used.
+// For ranges, the middle of the range is used. Tolerances are dropped.
+// notes contains all information lost during conversion.
+func parseGIFTNumerical(s string) (float64, []string, error) {
+	var notes []string
+	value := s
+	if indexUnescaped(s, "=") != -1 {
+		answers, err := parseGIFTAnswers(s)
+		if err != nil {
+			return 0, notes, err
+		}
+		value = ""
+		for i := range answers {
+			if answers[i].correct && (!answers[i].hasWeight || answers[i].weight == 100) {
+				value = answers[i].text
+				break
+			}
+		}
+		if value == "" {
+			return 0, notes, errors.New("no correct numerical answer found")
+		}
+		if len(answers) > 1 {
+			notes = append(notes, fmt.Sprintf("only the first correct numerical answer is imported (%d answers given)", len(answers)))
+		}
+	} else if i := indexUnescaped(value, "#"); i != -1 {
+		value = value[:i]
+	}
+	value = strings.TrimSpace(value)
+
+	if a, b, ok := strings.Cut(value, ".."); ok {
+		min, err := strconv.ParseFloat(strings.TrimSpace(a), 64)
+		if err != nil {
+			return 0, notes, fmt.Errorf("can not parse numerical range: %w", err)
+		}
+		max, err := strconv.ParseFloat(strings.TrimSpace(b), 64)
+		if err != nil {
+			return 0, notes, fmt.Errorf("can not parse numerical range: %w", err)
+		}
+		notes = append(notes, "numerical range imported as its middle value")
+		return (min + max) / 2, notes, nil
+	}
+	if v, tolerance, ok := strings.Cut(value, ":"); ok {
+		value = v
+		notes = append(notes, fmt.Sprintf("numerical tolerance %s is dropped", strings.TrimSpace(tolerance)))
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, notes, fmt.Errorf("can not parse numerical answer: %w", err)
+	}
+	return v, notes, nil
+}
+
+// indexUnescaped returns the index of the first occurrence of sub in s which is not escaped by a backslash or -1.
+func indexUnescaped(s, sub string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// countUnescaped counts all occurrences of r in s which are not escaped by a backslash.
+func countUnescaped(s string, r byte) int {
+	count := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == r {
+			count++
+		}
+	}
+	return count
+}
+
+// unescapeGIFT removes all GIFT escape sequences.
+func unescapeGIFT(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' {
+				sb.WriteByte('\n')
+			} else {
+				sb.WriteByte(s[i])
+			}
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package importer converts quiz banks from other systems into saved elements of ResponseGo.
+// Supported are the Moodle GIFT and Aiken formats.
+// All constructs which can not be converted are reported as problems together with their line.
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+const (
+	FormatGIFT  = "gift"
+	FormatAiken = "aiken"
+)
+
+// maxDescriptionLength is the number of characters of the text used in the description of an element.
+const maxDescriptionLength = 80
+
+// Element is a saved element as understood by the admin page.
+// Folder is optional and can be used by the element library.
+type Element struct {
+	From        string
+	Data        string
+	Description string
+	Folder      string `json:",omitempty"`
+}
+
+// Problem describes a part of the input which could not be imported (completely).
+// Line is the first line of the question in the input, starting at 1.
+type Problem struct {
+	Line    int
+	Message string
+}
+
+// String returns a human readable version of the problem.
+func (p Problem) String() string {
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+var aikenAnswerRegexp = regexp.MustCompile(`(?m)^\s*ANSWER:\s*[A-Za-z]\s*$`)
+
+// Detect guesses the format of the input. Inputs containing an Aiken answer line are assumed to be Aiken, everything else GIFT.
+func Detect(b []byte) string {
+	if aikenAnswerRegexp.Match(b) && !bytes.ContainsRune(b, '{') {
+		return FormatAiken
+	}
+	return FormatGIFT
+}
+
+// Import converts the input in the given format. If format is empty, the format is detected.
+// An error is only returned for unknown formats, all problems with the input are reported as Problem.
+func Import(b []byte, format string) ([]Element, []Problem, error) {
+	if format == "" {
+		format = Detect(b)
+	}
+	switch format {
+	case FormatGIFT:
+		e, p := ParseGIFT(b)
+		return e, p, nil
+	case FormatAiken:
+		e, p := ParseAiken(b)
+		return e, p, nil
+	}
+	return nil, nil, fmt.Errorf("unknown format %s", format)
+}
+
+// describe returns a description for an element in the same style as the admin page.
+func describe(name, text string) string {
+	r := []rune(text)
+	if len(r) > maxDescriptionLength {
+		text = string(r[:maxDescriptionLength]) + "[...]"
+	}
+	return fmt.Sprintf("%s: %s", name, text)
+}
+
+// choiceElement returns a Question or (if multiple is true) a MultipleChoice element.
+func choiceElement(question string, answers []string, multiple bool) (Element, error) {
+	if len(answers) == 0 {
+		return Element{}, fmt.Errorf("no answers found")
+	}
+	for i := range answers {
+		if answers[i] == "" {
+			return Element{}, fmt.Errorf("answer %d is empty", i+1)
+		}
+	}
+	b, err := json.Marshal(map[string]interface{}{"q": question, "o": answers})
+	if err != nil {
+		return Element{}, err
+	}
+
+	tl := translation.GetDefaultTranslation()
+	if multiple {
+		return Element{From: "MultipleChoice", Data: string(b), Description: describe(tl.DisplayMultipleChoice, question)}, nil
+	}
+	return Element{From: "Question", Data: string(b), Description: describe(tl.DisplayQuestion, question)}, nil
+}
+
+// numberElement returns a Number element with a correct value.
+func numberElement(question string, correct float64) (Element, error) {
+	data := map[string]string{"q": question, "c": strconv.FormatFloat(correct, 'f', -1, 64)}
+	if correct != float64(int64(correct)) {
+		data["d"] = "true"
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return Element{}, err
+	}
+	tl := translation.GetDefaultTranslation()
+	return Element{From: "Number", Data: string(b), Description: describe(tl.DisplayNumber, question)}, nil
+}
+
+// freeTextElement returns a FreeText element.
+func freeTextElement(question string) Element {
+	tl := translation.GetDefaultTranslation()
+	return Element{From: "FreeText", Data: question, Description: describe(tl.DisplayFreeText, question)}
+}
+
+// textElement returns a TextDisplay element. text is interpreted as Markdown.
+func textElement(text string) Element {
+	tl := translation.GetDefaultTranslation()
+	return Element{From: "TextDisplay", Data: text, Description: describe(tl.DisplayText, text)}
+}
@@ -0,0 +1,380 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Top-Ranger/responsego/registry"
+)
+
+const (
+	maxLibraryElements   = 1000
+	maxLibraryTags       = 20
+	maxLibraryShares     = 50
+	maxLibraryTextLength = 200
+	maxLibraryRequestKB  = 10 * 1024
+)
+
+// libraryElement is a saved element in the library of a user.
+// From, Data and Description are the same as for the saved elements stored in the browser.
+// Owner is only set for elements shared by other users.
+type libraryElement struct {
+	ID          string
+	From        string
+	Data        string
+	Description string
+	Folder      string
+	Tags        []string
+	SharedWith  []string
+	Owner       string `json:",omitempty"`
+}
+
+// userLibrary is the library of a single user as stored on disk.
+type userLibrary struct {
+	User     string
+	Elements []libraryElement
+}
+
+// libraryList is the view of a user on the library.
+type libraryList struct {
+	Own    []libraryElement
+	Shared []libraryElement
+}
+
+// libraryStore holds the libraries of all users. Each library is stored as a JSON file in path.
+type libraryStore struct {
+	l         sync.Mutex
+	path      string
+	libraries map[string]*userLibrary
+}
+
+// library is nil if no library path is configured.
+var library *libraryStore
+
+// loadLibrary loads all libraries from path. The directory is created if it does not exist.
+func loadLibrary(path string) (*libraryStore, error) {
+	err := os.MkdirAll(path, 0700)
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &libraryStore{path: path, libraries: make(map[string]*userLibrary)}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("can not read %s: %w", f, err)
+		}
+		var ul userLibrary
+		err = json.Unmarshal(b, &ul)
+		if err != nil {
+			return nil, fmt.Errorf("can not parse %s: %w", f, err)
+		}
+		s.libraries[ul.User] = &ul
+	}
+	return s, nil
+}
+
+// libraryFile returns the path of the library of a user. The user name is hashed to get a safe file name.
+func (s *libraryStore) libraryFile(user string) string {
+	h := sha256.Sum256([]byte(user))
+	return filepath.Join(s.path, hex.EncodeToString(h[:])+".json")
+}
+
+// save writes a library to disk. Caller must hold s.l.
+func (s *libraryStore) save(ul *userLibrary) error {
+	b, err := json.Marshal(ul)
+	if err != nil {
+		return err
+	}
+	file := s.libraryFile(ul.User)
+	err = os.WriteFile(file+".tmp", b, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(file+".tmp", file)
+}
+
+// copyLibrary returns a copy of the library of a user which can be changed without affecting the library in memory.
+// An empty library is returned if the user has no library. Caller must hold s.l.
+func (s *libraryStore) copyLibrary(user string) *userLibrary {
+	ul := &userLibrary{User: user, Elements: make([]libraryElement, 0)}
+	if current, ok := s.libraries[user]; ok {
+		ul.Elements = append(ul.Elements, current.Elements...)
+	}
+	return ul
+}
+
+// store saves a changed library. The library in memory is only replaced if saving succeeds. Caller must hold s.l.
+func (s *libraryStore) store(ul *userLibrary) error {
+	err := s.save(ul)
+	if err != nil {
+		return err
+	}
+	s.libraries[ul.User] = ul
+	return nil
+}
+
+// List returns all elements of a user and all elements shared with the user.
+// Elements are sorted by folder and description.
+func (s *libraryStore) List(user string) libraryList {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	list := libraryList{Own: make([]libraryElement, 0), Shared: make([]libraryElement, 0)}
+	if ul, ok := s.libraries[user]; ok {
+		list.Own = append(list.Own, ul.Elements...)
+	}
+	for owner, ul := range s.libraries {
+		if owner == user {
+			continue
+		}
+		for _, e := range ul.Elements {
+			for _, u := range e.SharedWith {
+				if u == user {
+					e.Owner = owner
+					e.SharedWith = nil
+					list.Shared = append(list.Shared, e)
+					break
+				}
+			}
+		}
+	}
+
+	less := func(l []libraryElement) func(i, j int) bool {
+		return func(i, j int) bool {
+			if l[i].Folder != l[j].Folder {
+				return l[i].Folder < l[j].Folder
+			}
+			return l[i].Description < l[j].Description
+		}
+	}
+	sort.SliceStable(list.Own, less(list.Own))
+	sort.SliceStable(list.Shared, less(list.Shared))
+	return list
+}
+
+// Add adds elements to the library of a user. New IDs are assigned to all elements.
+func (s *libraryStore) Add(user string, elements []libraryElement) error {
+	for i := range elements {
+		if elements[i].From == "" {
+			return fmt.Errorf("element %d: no plugin", i+1)
+		}
+		err := validateLibraryElement(user, &elements[i])
+		if err != nil {
+			return fmt.Errorf("element %d: %w", i+1, err)
+		}
+		b := make([]byte, 12)
+		_, err = rand.Read(b)
+		if err != nil {
+			return err
+		}
+		elements[i].ID = hex.EncodeToString(b)
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+	ul := s.copyLibrary(user)
+	if len(ul.Elements)+len(elements) > maxLibraryElements {
+		return fmt.Errorf("at most %d elements allowed", maxLibraryElements)
+	}
+	ul.Elements = append(ul.Elements, elements...)
+	return s.store(ul)
+}
+
+// Update changes the description, folder, tags and sharing of an element. The plugin and its data can not be changed.
+func (s *libraryStore) Update(user string, element libraryElement) error {
+	err := validateLibraryElement(user, &element)
+	if err != nil {
+		return err
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+	ul := s.copyLibrary(user)
+	for i := range ul.Elements {
+		if ul.Elements[i].ID != element.ID {
+			continue
+		}
+		ul.Elements[i].Description = element.Description
+		ul.Elements[i].Folder = element.Folder
+		ul.Elements[i].Tags = element.Tags
+		ul.Elements[i].SharedWith = element.SharedWith
+		return s.store(ul)
+	}
+	return fmt.Errorf("unknown element %s", element.ID)
+}
+
+// Delete removes an element from the library of a user.
+func (s *libraryStore) Delete(user, id string) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	ul := s.copyLibrary(user)
+	for i := range ul.Elements {
+		if ul.Elements[i].ID == id {
+			ul.Elements = append(ul.Elements[:i], ul.Elements[i+1:]...)
+			return s.store(ul)
+		}
+	}
+	return fmt.Errorf("unknown element %s", id)
+}
+
+// validateLibraryElement checks an element and normalises folder, tags and sharing.
+func validateLibraryElement(user string, e *libraryElement) error {
+	if e.From != "" {
+		if _, ok := registry.GetFeedbackPlugins(e.From); !ok {
+			return fmt.Errorf("unknown plugin %s", e.From)
+		}
+	}
+	e.Description = strings.TrimSpace(e.Description)
+	e.Folder = strings.Trim(strings.TrimSpace(e.Folder), "/")
+	if len([]rune(e.Description)) > maxLibraryTextLength || len([]rune(e.Folder)) > maxLibraryTextLength {
+		return errors.New("description or folder too long")
+	}
+	e.Owner = ""
+
+	var err error
+	e.Tags, err = normaliseLibraryList(e.Tags, maxLibraryTags, "")
+	if err != nil {
+		return fmt.Errorf("tags: %w", err)
+	}
+	e.SharedWith, err = normaliseLibraryList(e.SharedWith, maxLibraryShares, user)
+	if err != nil {
+		return fmt.Errorf("sharing: %w", err)
+	}
+	return nil
+}
+
+// normaliseLibraryList trims all entries and removes empty and duplicated entries as well as exclude.
+func normaliseLibraryList(l []string, max int, exclude string) ([]string, error) {
+	result := make([]string, 0, len(l))
+	seen := make(map[string]bool, len(l))
+	for _, s := range l {
+		s = strings.TrimSpace(s)
+		if s == "" || s == exclude || seen[s] {
+			continue
+		}
+		if len([]rune(s)) > maxLibraryTextLength {
+			return nil, fmt.Errorf("%s is too long", s)
+		}
+		seen[s] = true
+		result = append(result, s)
+	}
+	if len(result) > max {
+		return nil, fmt.Errorf("at most %d entries allowed", max)
+	}
+	return result, nil
+}
+
+// libraryAccess returns whether the request may access the library of the owner of the response.
+// Knowing the admin password is not sufficient since admin links are shared, the request must belong to a session of the owner.
+func (r *response) libraryAccess(req *http.Request) bool {
+	if library == nil || r.Owner == "" {
+		return false
+	}
+	user, ok := GetSessionUser(req)
+	return ok && user == r.Owner
+}
+
+// HandleLibrary allows the owner of a response to manage the library.
+// The request must belong to a session of the owner.
+// The command is given through the 'library' parameter:
+//   - list (GET): returns the library
+//   - add (POST): adds a JSON list of elements (e.g. saved elements downloaded from the browser)
+//   - update (POST): updates a single JSON element
+//   - delete (POST): deletes the element given by the 'id' parameter
+//
+// All commands return the library of the user as JSON.
+func (r *response) HandleLibrary(rw http.ResponseWriter, req *http.Request) {
+	if library == nil || r.Owner == "" {
+		http.Error(rw, "library not available", http.StatusNotFound)
+		return
+	}
+	if !r.libraryAccess(req) {
+		if config.LogLogin {
+			log.Printf("library (%s): access without session of owner from %s", r.Path, GetRealIP(req))
+		}
+		http.Error(rw, "403 Forbidden", http.StatusForbidden)
+		return
+	}
+
+	command := req.URL.Query().Get("library")
+	if command != "list" && req.Method != http.MethodPost {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var err error
+	switch command {
+	case "list":
+	case "add":
+		var elements []libraryElement
+		err = readLibraryRequest(rw, req, &elements)
+		if err == nil {
+			err = library.Add(r.Owner, elements)
+		}
+	case "update":
+		var element libraryElement
+		err = readLibraryRequest(rw, req, &element)
+		if err == nil {
+			err = library.Update(r.Owner, element)
+		}
+	case "delete":
+		err = library.Delete(r.Owner, req.URL.Query().Get("id"))
+	default:
+		http.Error(rw, "unknown command", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("library (%s): %s", r.Path, err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	b, err := json.Marshal(library.List(r.Owner))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(b)
+}
+
+// readLibraryRequest parses the JSON body of a request.
+func readLibraryRequest(rw http.ResponseWriter, req *http.Request, target interface{}) error {
+	b, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxLibraryRequestKB*1024))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, target)
+}
@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020,2022 Marcus Soll
+// Copyright 2020,2022,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -31,6 +31,7 @@ import (
 	_ "github.com/Top-Ranger/responsego/plugin"
 	"github.com/Top-Ranger/responsego/registry"
 	"github.com/Top-Ranger/responsego/translation"
+	"github.com/Top-Ranger/responsego/wordlist"
 )
 
 // ConfigStruct contains all configuration options for PollGo!
@@ -46,6 +47,13 @@ type ConfigStruct struct {
 	NeedAuthenticationForNew bool
 	Authenticater            string
 	AuthenticaterConfig      string
+	MaxUploadSizeKB          int
+	UploadPath               string
+	WordlistPath             string
+	RateLimits               map[string]RateLimitConfig
+	RateLimitIPFactor        float64
+	RateLimitDisconnect      int
+	LibraryPath              string
 }
 
 var config ConfigStruct
@@ -100,6 +108,13 @@ func printInfo() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(importCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "deck" {
+		os.Exit(deckCommand(os.Args[2:]))
+	}
+
 	printInfo()
 
 	configPath := flag.String("config", "./config.json", "Path to json config for ResponseGo!")
@@ -133,6 +148,27 @@ func main() {
 		authenticater = a
 	}
 
+	if config.UploadPath != "" {
+		err = os.MkdirAll(config.UploadPath, 0700)
+		if err != nil {
+			log.Panicf("main: Can not create upload path %s: %s", config.UploadPath, err.Error())
+		}
+	}
+
+	if config.WordlistPath != "" {
+		err = wordlist.LoadDirectory(config.WordlistPath)
+		if err != nil {
+			log.Panicf("main: Can not load word lists from %s: %s", config.WordlistPath, err.Error())
+		}
+	}
+
+	if config.LibraryPath != "" {
+		library, err = loadLibrary(config.LibraryPath)
+		if err != nil {
+			log.Panicf("main: Can not load element library from %s: %s", config.LibraryPath, err.Error())
+		}
+	}
+
 	RunServer()
 
 	s := make(chan os.Signal, 1)
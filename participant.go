@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"log"
+	"net/http"
+	"strings"
+)
+
+const participantCookie = "responsego_participant"
+
+// newParticipantID returns a new random pseudonymous participant identifier.
+func newParticipantID() string {
+	b := make([]byte, 20)
+	_, err := rand.Read(b)
+	if err != nil {
+		log.Printf("participant: can not create random id: %s", err.Error())
+	}
+	return base32.StdEncoding.EncodeToString(b)
+}
+
+// validParticipantID checks whether id looks like an identifier created by newParticipantID.
+func validParticipantID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := base32.StdEncoding.DecodeString(id)
+	return err == nil
+}
+
+// participantCookiePath returns the path of the response at key as seen by the browser.
+// If a reverse proxy removes config.ServerPath from the request, it is added again.
+func participantCookiePath(r *http.Request, key string) string {
+	if config.ServerPath != "" && !strings.HasPrefix(r.URL.Path, strings.Join([]string{config.ServerPath, "/"}, "")) {
+		return strings.Join([]string{config.ServerPath, "/", key}, "")
+	}
+	return strings.Join([]string{"/", key}, "")
+}
+
+// GetParticipantID returns the participant identifier of the request.
+// If the request does not contain a valid identifier, a new one is created and set as a cookie.
+// The cookie is only valid for the response at key.
+func GetParticipantID(rw http.ResponseWriter, r *http.Request, key string) string {
+	c, err := r.Cookie(participantCookie)
+	if err == nil && validParticipantID(c.Value) {
+		return c.Value
+	}
+
+	id := newParticipantID()
+	http.SetCookie(rw, &http.Cookie{
+		Name:     participantCookie,
+		Value:    id,
+		Path:     participantCookiePath(r, key),
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return id
+}
@@ -0,0 +1,548 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Top-Ranger/responsego/registry"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+func init() {
+	err := registry.RegisterFeedbackPlugin(func() registry.FeedbackPlugin { return new(appointment) }, "Appointment")
+	if err != nil {
+		panic(err)
+	}
+}
+
+const (
+	appointmentNo = iota
+	appointmentMaybe
+	appointmentYes
+)
+
+const appointmentSlotFormat = "2006-01-02 15:04"
+
+// appointmentMaxName is the maximum length of a name in characters.
+const appointmentMaxName = 80
+
+const appointmentConfig = `
+<h1>{{.Translation.DisplayAppointment}}</h1>
+<label for="AppointmentTitle">{{.Translation.Title}}:</label> <input id="AppointmentTitle" type="text"><br>
+<label for="AppointmentSlots">{{.Translation.AppointmentSlots}}:</label><br>
+<textarea class="fullwidth" id="AppointmentSlots" rows="6"></textarea><br>
+<input id="AppointmentSlotInput" type="datetime-local"> <button onclick="appointmentAddSlot()">{{.Translation.Add}}</button><br>
+<label for="AppointmentDuration">{{.Translation.Duration}}:</label> <input id="AppointmentDuration" type="number" min="1" value="60"> {{.Translation.Minutes}}<br>
+<p><button onclick="sendActivate('Appointment', appointmentGetData())">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('Appointment', appointmentGetData(), '{{.Translation.DisplayAppointment}}: '+document.getElementById('AppointmentTitle').value.substring(0,80)+(document.getElementById('AppointmentTitle').value.length>80?'[...]':''))">{{.Translation.SaveElement}}</button></p>
+
+<script>
+function appointmentAddSlot() {
+	let v = document.getElementById('AppointmentSlotInput').value;
+	if(v === "") {
+		return;
+	}
+	let t = document.getElementById('AppointmentSlots');
+	if(t.value !== "" && !t.value.endsWith("\n")) {
+		t.value += "\n";
+	}
+	t.value += v.replace("T", " ") + "\n";
+}
+
+function appointmentGetData() {
+	let data = {"Title": document.getElementById('AppointmentTitle').value, "Slots": document.getElementById('AppointmentSlots').value.split("\n"), "Duration": parseInt(document.getElementById('AppointmentDuration').value)};
+	return JSON.stringify(data);
+}
+</script>
+`
+
+var appointmentConfigTemplate = template.Must(template.New("appointmentConfig").Parse(appointmentConfig))
+
+type appointmentConfigStruct struct {
+	Translation translation.Translation
+}
+
+const appointmentUser = `
+<h1>{{.Title}}</h1>
+<p><label for="AppointmentName">{{.Translation.Name}}:</label> <input id="AppointmentName" type="text" maxlength="{{.MaxName}}"></p>
+<table>
+{{range $i, $e := .Slots}}
+	<tr>
+		<td>{{$e}}</td>
+		<td><input type="radio" id="Appointment_{{$i}}_yes" name="Appointment_{{$i}}" value="2"> <label for="Appointment_{{$i}}_yes">{{$.Translation.Yes}}</label></td>
+		<td><input type="radio" id="Appointment_{{$i}}_maybe" name="Appointment_{{$i}}" value="1"> <label for="Appointment_{{$i}}_maybe">{{$.Translation.Maybe}}</label></td>
+		<td><input type="radio" id="Appointment_{{$i}}_no" name="Appointment_{{$i}}" value="0" checked> <label for="Appointment_{{$i}}_no">{{$.Translation.No}}</label></td>
+	</tr>
+{{end}}
+</table>
+<p><button onclick="appointmentSend()">{{.Translation.Submit}}</button></p>
+
+<script>
+function appointmentSend() {
+	let name = document.getElementById('AppointmentName').value.trim();
+	if(name === "") {
+		document.getElementById('AppointmentName').focus();
+		return;
+	}
+	let answers = [];
+	for(let i = 0; i < {{len .Slots}}; i++) {
+		let e = document.querySelector('input[name="Appointment_' + i + '"]:checked');
+		answers.push(e === null ? 0 : parseInt(e.value));
+	}
+	sendData('Appointment', JSON.stringify({"Name": name, "Answers": answers}));
+}
+</script>
+`
+
+var appointmentUserTemplate = template.Must(template.New("appointmentUser").Parse(appointmentUser))
+
+type appointmentUserStruct struct {
+	Title       string
+	Slots       []string
+	MaxName     int
+	Translation translation.Translation
+}
+
+const appointmentAdmin = `
+<h1>{{.Title}}</h1>
+<p>{{.Translation.UpdateAll5Seconds}}</p>
+<table>
+	<tr>
+		<th>{{.Translation.Name}}</th>
+		{{range $i, $e := .Slots}}
+		<th {{if index $.Best $i}}class="highlight"{{end}}>{{$e}}</th>
+		{{end}}
+	</tr>
+	{{range $i, $e := .Participants}}
+	<tr>
+		<td>{{$e.Name}}</td>
+		{{range $j, $a := $e.Answers}}
+		<td>{{if eq $a 2}}{{$.Translation.Yes}}{{else if eq $a 1}}({{$.Translation.Maybe}}){{else}}-{{end}}</td>
+		{{end}}
+	</tr>
+	{{end}}
+	<tr>
+		<td><em>{{.Translation.Yes}}</em></td>
+		{{range $i, $e := .Yes}}
+		<td {{if index $.Best $i}}class="highlight"{{end}}><em>{{$e}}</em></td>
+		{{end}}
+	</tr>
+	<tr>
+		<td><em>{{.Translation.Maybe}}</em></td>
+		{{range $i, $e := .Maybe}}
+		<td {{if index $.Best $i}}class="highlight"{{end}}><em>{{$e}}</em></td>
+		{{end}}
+	</tr>
+</table>
+`
+
+var appointmentAdminTemplate = template.Must(template.New("appointmentAdmin").Parse(appointmentAdmin))
+
+type appointmentParticipant struct {
+	Name    string
+	Answers []int
+}
+
+type appointmentAdminStruct struct {
+	Title        string
+	Slots        []string
+	Participants []appointmentParticipant
+	Yes          []int
+	Maybe        []int
+	Best         []bool
+	Translation  translation.Translation
+}
+
+type appointmentGetConfig struct {
+	Title    string
+	Slots    []string
+	Duration int
+}
+
+type appointmentUserInput struct {
+	Name    string
+	Answers []int
+}
+
+type appointment struct {
+	adminHTML        chan<- template.HTML
+	userHTML         chan<- template.HTML
+	adminInput       <-chan []byte
+	userInput        <-chan []byte
+	participantInput <-chan registry.ParticipantInput
+	ctx              context.Context
+	cancel           context.CancelFunc
+
+	title         string
+	slots         []time.Time
+	duration      time.Duration
+	participants  map[string]appointmentParticipant
+	anonymous     int
+	changed       bool
+	userHTMLCache template.HTML
+	l             sync.Mutex
+}
+
+func (a *appointment) ConfigHTML() (string, template.HTML) {
+	tl := translation.GetDefaultTranslation()
+	td := appointmentConfigStruct{
+		Translation: tl,
+	}
+	var buf bytes.Buffer
+	err := appointmentConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing appointment config: %s", err.Error())
+	}
+
+	return tl.DisplayAppointment, template.HTML(buf.Bytes())
+}
+
+func (a *appointment) AdminHTMLChannel(c chan<- template.HTML) {
+	a.adminHTML = c
+}
+
+func (a *appointment) UserHTMLChannel(c chan<- template.HTML) {
+	a.userHTML = c
+}
+
+func (a *appointment) ReceiveUserChannel(c <-chan []byte) {
+	a.userInput = c
+}
+
+func (a *appointment) ReceiveUserParticipantChannel(c <-chan registry.ParticipantInput) {
+	a.participantInput = c
+}
+
+func (a *appointment) ReceiveAdminChannel(c <-chan []byte) {
+	a.adminInput = c
+}
+
+func (a *appointment) Activate(b []byte) error {
+	var config appointmentGetConfig
+	err := json.Unmarshal(b, &config)
+	if err != nil {
+		return err
+	}
+
+	if config.Title == "" {
+		return errors.New("appointment: no title found")
+	}
+	if config.Duration <= 0 {
+		return errors.New("appointment: duration must be positive")
+	}
+
+	a.title = config.Title
+	a.duration = time.Duration(config.Duration) * time.Minute
+	seen := make(map[time.Time]bool)
+	for i := range config.Slots {
+		s := strings.TrimSpace(config.Slots[i])
+		if s == "" {
+			continue
+		}
+		t, err := time.ParseInLocation(appointmentSlotFormat, s, time.Local)
+		if err != nil {
+			return fmt.Errorf("appointment: can not parse slot '%s' (expected format YYYY-MM-DD HH:MM): %w", s, err)
+		}
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		a.slots = append(a.slots, t)
+	}
+	if len(a.slots) == 0 {
+		return errors.New("appointment: at least one slot is needed")
+	}
+	sort.Slice(a.slots, func(i, j int) bool { return a.slots[i].Before(a.slots[j]) })
+
+	a.participants = make(map[string]appointmentParticipant)
+
+	td := appointmentUserStruct{
+		Title:       a.title,
+		Slots:       a.slotNames(),
+		MaxName:     appointmentMaxName,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err = appointmentUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing appointment user: %s", err.Error())
+		return err
+	}
+	a.userHTMLCache = template.HTML(buf.Bytes())
+
+	go func() {
+		a.userHTML <- a.userHTMLCache
+	}()
+	go func() {
+		a.adminHTML <- a.getAdminPage()
+	}()
+
+	a.ctx = context.Background()
+	a.ctx, a.cancel = context.WithCancel(a.ctx)
+	go func() {
+		done := a.ctx.Done()
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.adminInput:
+			case b := <-a.userInput:
+				a.receive(registry.ParticipantInput{Data: b})
+
+			case p := <-a.participantInput:
+				a.receive(p)
+
+			case <-ticker.C:
+				a.l.Lock()
+				changed := a.changed
+				a.changed = false
+				a.l.Unlock()
+				if changed {
+					a.adminHTML <- a.getAdminPage()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// receive handles the answers of a participant.
+// Submitting again replaces the old answers of the participant. Inputs without a participant are always added as a new row.
+func (a *appointment) receive(p registry.ParticipantInput) {
+	var input appointmentUserInput
+	err := json.Unmarshal(p.Data, &input)
+	if err != nil {
+		return
+	}
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" || utf8.RuneCountInString(input.Name) > appointmentMaxName || len(input.Answers) != len(a.slots) {
+		return
+	}
+	for i := range input.Answers {
+		if input.Answers[i] < appointmentNo || input.Answers[i] > appointmentYes {
+			return
+		}
+	}
+
+	a.l.Lock()
+	defer a.l.Unlock()
+	key := p.Participant
+	if key == "" {
+		a.anonymous++
+		key = fmt.Sprintf("anonymous-%d", a.anonymous)
+	}
+	a.participants[key] = appointmentParticipant{Name: input.Name, Answers: input.Answers}
+	a.changed = true
+}
+
+func (a *appointment) GetLastHTMLUser() template.HTML {
+	return a.userHTMLCache
+}
+
+func (a *appointment) GetLastHTMLAdmin() template.HTML {
+	return a.getAdminPage()
+}
+
+func (a *appointment) Deactivate() {
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
+
+func (a *appointment) slotNames() []string {
+	s := make([]string, len(a.slots))
+	for i := range a.slots {
+		s[i] = a.slots[i].Format(appointmentSlotFormat)
+	}
+	return s
+}
+
+// sortedParticipants returns all participants sorted by name.
+// Caller must hold l.
+func (a *appointment) sortedParticipants() []appointmentParticipant {
+	p := make([]appointmentParticipant, 0, len(a.participants))
+	for k := range a.participants {
+		p = append(p, a.participants[k])
+	}
+	sort.Slice(p, func(i, j int) bool { return p[i].Name < p[j].Name })
+	return p
+}
+
+// count returns the number of yes and maybe answers per slot as well as the best slots.
+// The best slots have the most yes answers, ties are broken by the number of maybe answers.
+// Caller must hold l.
+func (a *appointment) count() ([]int, []int, []bool) {
+	yes := make([]int, len(a.slots))
+	maybe := make([]int, len(a.slots))
+	for k := range a.participants {
+		for i, v := range a.participants[k].Answers {
+			switch v {
+			case appointmentYes:
+				yes[i]++
+			case appointmentMaybe:
+				maybe[i]++
+			}
+		}
+	}
+
+	best := make([]bool, len(a.slots))
+	bestYes, bestMaybe := 0, 0
+	for i := range a.slots {
+		if yes[i] > bestYes || (yes[i] == bestYes && maybe[i] > bestMaybe) {
+			bestYes, bestMaybe = yes[i], maybe[i]
+		}
+	}
+	if bestYes == 0 && bestMaybe == 0 {
+		return yes, maybe, best
+	}
+	for i := range a.slots {
+		best[i] = yes[i] == bestYes && maybe[i] == bestMaybe
+	}
+	return yes, maybe, best
+}
+
+func (a *appointment) getAdminPage() template.HTML {
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	yes, maybe, best := a.count()
+	td := appointmentAdminStruct{
+		Title:        a.title,
+		Slots:        a.slotNames(),
+		Participants: a.sortedParticipants(),
+		Yes:          yes,
+		Maybe:        maybe,
+		Best:         best,
+		Translation:  translation.GetDefaultTranslation(),
+	}
+
+	var buf bytes.Buffer
+	err := appointmentAdminTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing appointment admin: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+// iCalendar returns an iCalendar file (RFC 5545) containing one event per slot. Caller must hold l.
+func (a *appointment) iCalendar(slots []time.Time) []byte {
+	const icsTime = "20060102T150405Z"
+	escape := strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\n", "\\n", "\r", "")
+
+	var buf bytes.Buffer
+	buf.WriteString("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//ResponseGo!//Appointment//EN\r\n")
+	now := time.Now().UTC().Format(icsTime)
+	for i := range slots {
+		buf.WriteString("BEGIN:VEVENT\r\n")
+		fmt.Fprintf(&buf, "UID:%d-%d@responsego\r\n", slots[i].Unix(), i)
+		fmt.Fprintf(&buf, "DTSTAMP:%s\r\n", now)
+		fmt.Fprintf(&buf, "DTSTART:%s\r\n", slots[i].UTC().Format(icsTime))
+		fmt.Fprintf(&buf, "DTEND:%s\r\n", slots[i].Add(a.duration).UTC().Format(icsTime))
+		fmt.Fprintf(&buf, "SUMMARY:%s\r\n", escape.Replace(a.title))
+		buf.WriteString("END:VEVENT\r\n")
+	}
+	buf.WriteString("END:VCALENDAR\r\n")
+	return buf.Bytes()
+}
+
+func (a *appointment) GetAdminDownload() []byte {
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	return a.csv()
+}
+
+func (a *appointment) DownloadFormats() []string {
+	return []string{"csv", "ics"}
+}
+
+// GetAdminDownloadFormat returns all answers as CSV or the best slots as iCalendar file.
+func (a *appointment) GetAdminDownloadFormat(format string) []byte {
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	switch format {
+	case "csv":
+		return a.csv()
+	case "ics":
+		_, _, best := a.count()
+		bestSlots := make([]time.Time, 0)
+		for i := range best {
+			if best[i] {
+				bestSlots = append(bestSlots, a.slots[i])
+			}
+		}
+		return a.iCalendar(bestSlots)
+	}
+	return nil
+}
+
+// csv returns all answers together with the number of yes and maybe answers per slot. Caller must hold l.
+func (a *appointment) csv() []byte {
+	tl := translation.GetDefaultTranslation()
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.Write(append([]string{tl.Name}, a.slotNames()...))
+	for _, p := range a.sortedParticipants() {
+		record := make([]string, 0, len(p.Answers)+1)
+		record = append(record, p.Name)
+		for _, v := range p.Answers {
+			switch v {
+			case appointmentYes:
+				record = append(record, tl.Yes)
+			case appointmentMaybe:
+				record = append(record, tl.Maybe)
+			default:
+				record = append(record, tl.No)
+			}
+		}
+		w.Write(record)
+	}
+
+	yes, maybe, _ := a.count()
+	record := []string{tl.Yes}
+	for i := range yes {
+		record = append(record, fmt.Sprint(yes[i]))
+	}
+	w.Write(record)
+	record = []string{tl.Maybe}
+	for i := range maybe {
+		record = append(record, fmt.Sprint(maybe[i]))
+	}
+	w.Write(record)
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return []byte(err.Error())
+	}
+	return buf.Bytes()
+}
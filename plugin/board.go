@@ -0,0 +1,714 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Top-Ranger/responsego/registry"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+func init() {
+	err := registry.RegisterFeedbackPlugin(func() registry.FeedbackPlugin { return new(board) }, "Board")
+	if err != nil {
+		panic(err)
+	}
+}
+
+const (
+	boardCollect = iota
+	boardVote
+	boardClosed
+)
+
+const (
+	boardDefaultVotes  = 3
+	boardMaxCardLength = 500
+	boardMaxCards      = 1000
+)
+
+const boardConfig = `
+<h1>{{.Translation.DisplayBoard}}</h1>
+<label for="BoardTitle">{{.Translation.Title}}:</label> <input id="BoardTitle" type="text"><br>
+<label for="BoardColumns">{{.Translation.BoardColumns}}:</label><br>
+<textarea id="BoardColumns" rows="4">{{.DefaultColumns}}</textarea><br>
+<label for="BoardVotes">{{.Translation.VotesPerParticipant}}:</label> <input id="BoardVotes" type="number" min="1" value="{{.DefaultVotes}}"><br>
+<p><button onclick="sendActivate('Board', boardGetData())">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('Board', boardGetData(), '{{.Translation.DisplayBoard}}: '+document.getElementById('BoardTitle').value.substring(0,80)+(document.getElementById('BoardTitle').value.length>80?'[...]':''))">{{.Translation.SaveElement}}</button></p>
+
+<script>
+function boardGetData() {
+	let columns = document.getElementById('BoardColumns').value.split("\n").map(function(c) { return c.trim(); }).filter(function(c) { return c !== ""; });
+	let data = {"Title": document.getElementById('BoardTitle').value, "Columns": columns, "Votes": parseInt(document.getElementById('BoardVotes').value)};
+	return JSON.stringify(data);
+}
+</script>
+`
+
+var boardConfigTemplate = template.Must(template.New("boardConfig").Parse(boardConfig))
+
+type boardConfigStruct struct {
+	DefaultColumns string
+	DefaultVotes   int
+	Translation    translation.Translation
+}
+
+const boardHTML = `
+<h1>{{.Title}}</h1>
+{{if eq .Phase .Collect}}
+<p><em>{{.Translation.BoardCollectPhase}}</em></p>
+{{else if eq .Phase .Vote}}
+<p><em>{{.Translation.BoardVotePhase}}</em>{{if not .Admin}} <strong>{{.Translation.RemainingVotes}}: <span id="boardRemaining"></span></strong>{{end}}</p>
+{{end}}
+{{if .Admin}}
+<p>
+{{if eq .Phase .Collect}}<button onclick="boardCommand({'Action': 'phase', 'Phase': {{.Vote}}})">{{.Translation.StartVoting}}</button>{{end}}
+{{if ne .Phase .Closed}}<button onclick="boardCommand({'Action': 'phase', 'Phase': {{.Closed}}})">{{.Translation.Finish}}</button>{{end}}
+</p>
+{{end}}
+<div class="board" id="board"></div>
+
+<script>
+var boardColumns = {{.Columns}};
+var boardCards = {};
+var boardPhase = {{.Phase}};
+var boardAdmin = {{.Admin}};
+var boardStorage = "responsego_board_{{.ID}}";
+var boardVotesTotal = {{.Votes}};
+
+{{range $i, $e := .Cards}}
+boardCards[{{$e.ID}}] = {{$e}};
+{{end}}
+
+function boardCommand(c) {
+	sendData('Board', JSON.stringify(c));
+}
+
+function boardUsedVotes() {
+	try {
+		return parseInt(localStorage.getItem(boardStorage)) || 0;
+	} catch (e) {
+		return 0;
+	}
+}
+
+function boardUseVote() {
+	try {
+		localStorage.setItem(boardStorage, String(boardUsedVotes() + 1));
+	} catch (e) {
+		console.log(e);
+	}
+}
+
+function boardRender() {
+	let b = document.getElementById("board");
+	if(b === null) {
+		return;
+	}
+	b.innerHTML = "";
+	let remaining = boardVotesTotal - boardUsedVotes();
+	let r = document.getElementById("boardRemaining");
+	if(r !== null) {
+		r.textContent = Math.max(0, remaining);
+	}
+
+	for(let c = 0; c < boardColumns.length; c++) {
+		let column = document.createElement("DIV");
+		column.classList.add("boardcolumn");
+		let h = document.createElement("H2");
+		h.textContent = boardColumns[c];
+		column.appendChild(h);
+
+		if(boardAdmin) {
+			column.ondragover = function(e) {
+				e.preventDefault();
+			};
+			column.ondrop = function(e) {
+				e.preventDefault();
+				let id = parseInt(e.dataTransfer.getData("text/plain"));
+				if(!isNaN(id)) {
+					boardCommand({"Action": "move", "ID": id, "Column": c});
+				}
+			};
+		}
+
+		let cards = Object.values(boardCards).filter(function(card) { return card.Column === c; });
+		if(boardPhase === {{.Closed}}) {
+			cards.sort(function(a, b) { return b.Votes - a.Votes || a.ID - b.ID; });
+		} else {
+			cards.sort(function(a, b) { return a.ID - b.ID; });
+		}
+
+		for(let i = 0; i < cards.length; i++) {
+			let card = cards[i];
+			let d = document.createElement("DIV");
+			d.classList.add("boardcard");
+			let texts = [card.Text].concat(card.Merged || []);
+			for(let t = 0; t < texts.length; t++) {
+				let p = document.createElement("P");
+				p.textContent = texts[t];
+				d.appendChild(p);
+			}
+			if(boardAdmin || boardPhase === {{.Closed}}) {
+				let v = document.createElement("P");
+				v.innerHTML = "<strong></strong>";
+				v.firstChild.textContent = "#" + card.ID + " – {{.Translation.Votes}}: " + card.Votes;
+				d.appendChild(v);
+			}
+			if(!boardAdmin && boardPhase === {{.Vote}}) {
+				let vote = document.createElement("BUTTON");
+				vote.textContent = "{{.Translation.Vote}}";
+				vote.disabled = remaining <= 0;
+				vote.onclick = function() {
+					if(boardVotesTotal - boardUsedVotes() <= 0) {
+						return;
+					}
+					boardCommand({"Vote": card.ID});
+					boardUseVote();
+					boardRender();
+				};
+				d.appendChild(vote);
+			}
+			if(boardAdmin) {
+				d.draggable = true;
+				d.ondragstart = function(e) {
+					e.dataTransfer.setData("text/plain", String(card.ID));
+				};
+				d.ondragover = function(e) {
+					e.preventDefault();
+				};
+				d.ondrop = function(e) {
+					e.preventDefault();
+					e.stopPropagation();
+					let id = parseInt(e.dataTransfer.getData("text/plain"));
+					if(!isNaN(id) && id !== card.ID) {
+						boardCommand({"Action": "merge", "ID": id, "Target": card.ID});
+					}
+				};
+				let select = document.createElement("SELECT");
+				for(let o = 0; o < boardColumns.length; o++) {
+					let option = document.createElement("OPTION");
+					option.value = o;
+					option.textContent = boardColumns[o];
+					option.selected = o === c;
+					select.appendChild(option);
+				}
+				select.onchange = function() {
+					boardCommand({"Action": "move", "ID": card.ID, "Column": parseInt(select.value)});
+				};
+				d.appendChild(select);
+				let merge = document.createElement("BUTTON");
+				merge.textContent = "{{.Translation.Merge}}";
+				merge.onclick = function() {
+					let target = prompt("{{.Translation.MergeInto}} #", "");
+					if(target !== null && !isNaN(parseInt(target.replace("#", "")))) {
+						boardCommand({"Action": "merge", "ID": card.ID, "Target": parseInt(target.replace("#", ""))});
+					}
+				};
+				d.appendChild(merge);
+				let remove = document.createElement("BUTTON");
+				remove.textContent = "{{.Translation.Remove}}";
+				remove.onclick = function() {
+					boardCommand({"Action": "delete", "ID": card.ID});
+				};
+				d.appendChild(remove);
+			}
+			column.appendChild(d);
+		}
+
+		if(boardPhase === {{.Collect}}) {
+			let input = document.createElement("TEXTAREA");
+			input.maxLength = {{.MaxLength}};
+			column.appendChild(input);
+			let add = document.createElement("BUTTON");
+			add.textContent = "{{.Translation.Add}}";
+			add.onclick = function() {
+				if(input.value.trim() === "") {
+					return;
+				}
+				if(boardAdmin) {
+					boardCommand({"Action": "add", "Column": c, "Text": input.value});
+				} else {
+					boardCommand({"Add": {"Column": c, "Text": input.value}});
+				}
+				input.value = "";
+			};
+			column.appendChild(add);
+		}
+		b.appendChild(column);
+	}
+}
+
+data_function = function(b) {
+	try {
+		let update = JSON.parse(b);
+		for(let i = 0; i < update.length; i++) {
+			if(update[i].Removed) {
+				delete boardCards[update[i].ID];
+			} else {
+				boardCards[update[i].ID] = update[i];
+			}
+		}
+		boardRender();
+	} catch (e) {
+		console.log(e);
+	}
+};
+
+boardRender();
+</script>
+`
+
+var boardHTMLTemplate = template.Must(template.New("boardHTML").Parse(boardHTML))
+
+type boardHTMLStruct struct {
+	ID          int64
+	Title       string
+	Columns     []string
+	Cards       []boardCard
+	Votes       int
+	Phase       int
+	Admin       bool
+	MaxLength   int
+	Collect     int
+	Vote        int
+	Closed      int
+	Translation translation.Translation
+}
+
+type boardGetConfig struct {
+	Title   string
+	Columns []string
+	Votes   int
+}
+
+type boardCard struct {
+	ID      int
+	Column  int
+	Text    string
+	Merged  []string `json:",omitempty"`
+	Votes   int
+	Removed bool `json:",omitempty"`
+}
+
+type boardUserInput struct {
+	Add *struct {
+		Column int
+		Text   string
+	}
+	Vote *int
+}
+
+type boardCommand struct {
+	Action string
+	ID     int
+	Column int
+	Target int
+	Text   string
+	Phase  int
+}
+
+type board struct {
+	adminHTML        chan<- template.HTML
+	userHTML         chan<- template.HTML
+	adminInput       <-chan []byte
+	userInput        <-chan []byte
+	participantInput <-chan registry.ParticipantInput
+	adminData        chan<- []byte
+	userData         chan<- []byte
+	ctx              context.Context
+	cancel           context.CancelFunc
+
+	id        int64
+	title     string
+	columns   []string
+	votes     int
+	phase     int
+	cards     map[int]*boardCard
+	nextID    int
+	votesUsed map[string]int
+	changed   map[int]bool // IDs of cards which changed since the last update
+	l         sync.Mutex
+}
+
+func (b *board) ConfigHTML() (string, template.HTML) {
+	tl := translation.GetDefaultTranslation()
+	td := boardConfigStruct{
+		DefaultColumns: strings.Join([]string{tl.BoardKeep, tl.BoardStop, tl.BoardStart}, "\n"),
+		DefaultVotes:   boardDefaultVotes,
+		Translation:    tl,
+	}
+	var buf bytes.Buffer
+	err := boardConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing board config: %s", err.Error())
+	}
+
+	return tl.DisplayBoard, template.HTML(buf.Bytes())
+}
+
+func (b *board) AdminHTMLChannel(c chan<- template.HTML) {
+	b.adminHTML = c
+}
+
+func (b *board) UserHTMLChannel(c chan<- template.HTML) {
+	b.userHTML = c
+}
+
+func (b *board) ReceiveUserChannel(c <-chan []byte) {
+	b.userInput = c
+}
+
+func (b *board) ReceiveUserParticipantChannel(c <-chan registry.ParticipantInput) {
+	b.participantInput = c
+}
+
+func (b *board) ReceiveAdminChannel(c <-chan []byte) {
+	b.adminInput = c
+}
+
+func (b *board) AdminDataChannel(c chan<- []byte) {
+	b.adminData = c
+}
+
+func (b *board) UserDataChannel(c chan<- []byte) {
+	b.userData = c
+}
+
+func (b *board) Activate(by []byte) error {
+	var config boardGetConfig
+	err := json.Unmarshal(by, &config)
+	if err != nil {
+		return err
+	}
+
+	for i := range config.Columns {
+		c := strings.TrimSpace(config.Columns[i])
+		if c != "" {
+			b.columns = append(b.columns, c)
+		}
+	}
+	if len(b.columns) == 0 {
+		return errors.New("board: no columns found")
+	}
+	if config.Votes <= 0 {
+		config.Votes = boardDefaultVotes
+	}
+
+	b.id = time.Now().UnixNano()
+	b.title = config.Title
+	b.votes = config.Votes
+	b.phase = boardCollect
+	b.cards = make(map[int]*boardCard)
+	b.votesUsed = make(map[string]int)
+	b.changed = make(map[int]bool)
+
+	go func() { b.userHTML <- b.getHTML(false) }()
+	go func() { b.adminHTML <- b.getHTML(true) }()
+	b.ctx = context.Background()
+	b.ctx, b.cancel = context.WithCancel(b.ctx)
+	go b.worker(b.ctx)
+
+	return nil
+}
+
+func (b *board) GetLastHTMLUser() template.HTML {
+	return b.getHTML(false)
+}
+
+func (b *board) GetLastHTMLAdmin() template.HTML {
+	return b.getHTML(true)
+}
+
+func (b *board) Deactivate() {
+	if b.cancel != nil {
+		b.cancel()
+	}
+}
+
+// userCard returns the card as seen by participants. Votes are hidden until the board is closed. Caller must hold l.
+func (b *board) userCard(c boardCard) boardCard {
+	if b.phase != boardClosed {
+		c.Votes = 0
+	}
+	return c
+}
+
+func (b *board) getHTML(admin bool) template.HTML {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	td := boardHTMLStruct{
+		ID:          b.id,
+		Title:       b.title,
+		Columns:     b.columns,
+		Cards:       make([]boardCard, 0, len(b.cards)),
+		Votes:       b.votes,
+		Phase:       b.phase,
+		Admin:       admin,
+		MaxLength:   boardMaxCardLength,
+		Collect:     boardCollect,
+		Vote:        boardVote,
+		Closed:      boardClosed,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	for _, c := range b.cards {
+		if admin {
+			td.Cards = append(td.Cards, *c)
+		} else {
+			td.Cards = append(td.Cards, b.userCard(*c))
+		}
+	}
+	sort.Slice(td.Cards, func(i, j int) bool { return td.Cards[i].ID < td.Cards[j].ID })
+
+	var buf bytes.Buffer
+	err := boardHTMLTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing board: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+// addCard adds a new card. Caller must hold l.
+func (b *board) addCard(column int, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > boardMaxCardLength || column < 0 || column >= len(b.columns) || len(b.cards) >= boardMaxCards {
+		return
+	}
+	b.cards[b.nextID] = &boardCard{ID: b.nextID, Column: column, Text: text}
+	b.changed[b.nextID] = true
+	b.nextID++
+}
+
+// moderate applies a command of an admin. It returns whether the phase changed. Caller must hold l.
+func (b *board) moderate(c boardCommand) bool {
+	switch c.Action {
+	case "add":
+		b.addCard(c.Column, c.Text)
+	case "move":
+		card, ok := b.cards[c.ID]
+		if ok && c.Column >= 0 && c.Column < len(b.columns) {
+			card.Column = c.Column
+			b.changed[c.ID] = true
+		}
+	case "merge":
+		card, ok := b.cards[c.ID]
+		target, okTarget := b.cards[c.Target]
+		if ok && okTarget && c.ID != c.Target {
+			target.Merged = append(target.Merged, card.Text)
+			target.Merged = append(target.Merged, card.Merged...)
+			target.Votes += card.Votes
+			delete(b.cards, c.ID)
+			b.changed[c.ID] = true
+			b.changed[c.Target] = true
+		}
+	case "delete":
+		if _, ok := b.cards[c.ID]; ok {
+			delete(b.cards, c.ID)
+			b.changed[c.ID] = true
+		}
+	case "phase":
+		if c.Phase > b.phase && c.Phase <= boardClosed {
+			b.phase = c.Phase
+			return true
+		}
+	}
+	return false
+}
+
+// receive handles an input of a participant. Caller must hold l.
+func (b *board) receive(p registry.ParticipantInput) {
+	var input boardUserInput
+	err := json.Unmarshal(p.Data, &input)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case input.Add != nil:
+		if b.phase == boardCollect {
+			b.addCard(input.Add.Column, input.Add.Text)
+		}
+	case input.Vote != nil:
+		card, ok := b.cards[*input.Vote]
+		if b.phase != boardVote || !ok || b.votesUsed[p.Participant] >= b.votes {
+			return
+		}
+		b.votesUsed[p.Participant]++
+		card.Votes++
+		b.changed[card.ID] = true
+	}
+}
+
+func (b *board) worker(ctx context.Context) {
+	done := ctx.Done()
+	// Updates are bundled to avoid flooding connections while many participants are voting
+	t := time.NewTicker(500 * time.Millisecond)
+	defer t.Stop()
+	for {
+		select {
+		case by := <-b.adminInput:
+			var c boardCommand
+			err := json.Unmarshal(by, &c)
+			if err != nil {
+				continue
+			}
+			b.l.Lock()
+			phaseChanged := b.moderate(c)
+			b.l.Unlock()
+			if phaseChanged {
+				b.l.Lock()
+				b.changed = make(map[int]bool)
+				b.l.Unlock()
+				b.adminHTML <- b.getHTML(true)
+				b.userHTML <- b.getHTML(false)
+				continue
+			}
+			b.sendUpdate()
+		case by := <-b.userInput:
+			b.l.Lock()
+			b.receive(registry.ParticipantInput{Data: by})
+			b.l.Unlock()
+		case p := <-b.participantInput:
+			b.l.Lock()
+			b.receive(p)
+			b.l.Unlock()
+		case <-t.C:
+			b.sendUpdate()
+		case <-done:
+			return
+		}
+	}
+}
+
+// sendUpdate sends all changed cards to admins and participants.
+func (b *board) sendUpdate() {
+	b.l.Lock()
+	if len(b.changed) == 0 {
+		b.l.Unlock()
+		return
+	}
+	admin := make([]boardCard, 0, len(b.changed))
+	user := make([]boardCard, 0, len(b.changed))
+	for id := range b.changed {
+		c, ok := b.cards[id]
+		if !ok {
+			admin = append(admin, boardCard{ID: id, Removed: true})
+			user = append(user, boardCard{ID: id, Removed: true})
+			continue
+		}
+		admin = append(admin, *c)
+		user = append(user, b.userCard(*c))
+	}
+	b.changed = make(map[int]bool)
+	b.l.Unlock()
+
+	ab, err := json.Marshal(admin)
+	if err != nil {
+		log.Printf("board: Error marshaling update: (%s)", err.Error())
+		return
+	}
+	ub, err := json.Marshal(user)
+	if err != nil {
+		log.Printf("board: Error marshaling update: (%s)", err.Error())
+		return
+	}
+	b.adminData <- ab
+	b.userData <- ub
+}
+
+type boardResultStruct struct {
+	Title   string
+	Columns []string
+	Cards   []boardCard
+}
+
+func (b *board) GetAdminDownload() []byte {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	r := boardResultStruct{
+		Title:   b.title,
+		Columns: b.columns,
+		Cards:   b.sortedCards(),
+	}
+	by, err := json.Marshal(r)
+	if err != nil {
+		return []byte(err.Error())
+	}
+	return by
+}
+
+func (b *board) DownloadFormats() []string {
+	return []string{"csv"}
+}
+
+// GetAdminDownloadFormat returns all cards as CSV.
+func (b *board) GetAdminDownloadFormat(format string) []byte {
+	if format != "csv" {
+		return nil
+	}
+
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"ID", "Column", "Text", "Merged", "Votes"})
+	for _, c := range b.sortedCards() {
+		column := ""
+		if c.Column >= 0 && c.Column < len(b.columns) {
+			column = b.columns[c.Column]
+		}
+		w.Write([]string{strconv.Itoa(c.ID), column, c.Text, strings.Join(c.Merged, "\n"), strconv.Itoa(c.Votes)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return []byte(err.Error())
+	}
+	return buf.Bytes()
+}
+
+// sortedCards returns all cards sorted by column and votes. Caller must hold l.
+func (b *board) sortedCards() []boardCard {
+	cards := make([]boardCard, 0, len(b.cards))
+	for _, c := range b.cards {
+		cards = append(cards, *c)
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		if cards[i].Column != cards[j].Column {
+			return cards[i].Column < cards[j].Column
+		}
+		if cards[i].Votes != cards[j].Votes {
+			return cards[i].Votes > cards[j].Votes
+		}
+		return cards[i].ID < cards[j].ID
+	})
+	return cards
+}
@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Top-Ranger/responsego/helper"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+// choiceOptionsKey is the key of the options in the configuration of the Question and MultipleChoice plugins.
+const choiceOptionsKey = "o"
+
+// choiceOption is a single option of the Question and MultipleChoice plugins.
+// If Markdown is true, the text is rendered through helper.Format.
+// In the configuration, an option is either an object or a string (an option without Markdown).
+type choiceOption struct {
+	Text     string `json:"t"`
+	Markdown bool   `json:"md,omitempty"`
+}
+
+func (o *choiceOption) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		o.Text = s
+		o.Markdown = false
+		return nil
+	}
+	type plain choiceOption
+	err := json.Unmarshal(b, (*plain)(o))
+	if err != nil {
+		return fmt.Errorf("option must be a string or an object")
+	}
+	return nil
+}
+
+// HTML returns the option as it is shown to the participants.
+func (o choiceOption) HTML() template.HTML {
+	if o.Markdown {
+		return helper.Format([]byte(o.Text))
+	}
+	return template.HTML(template.HTMLEscapeString(o.Text))
+}
+
+// choiceRevealKey is the key of the reveal mode in the configuration of the Question and MultipleChoice plugins.
+const choiceRevealKey = "r"
+
+// Reveal modes control who sees the results of a poll before and after it is closed.
+const (
+	revealAdmin  revealMode = ""       // live to the admin, to everyone after closing
+	revealLive   revealMode = "live"   // live to everyone
+	revealHidden revealMode = "hidden" // to everyone after closing, the admin only sees the number of submissions before
+	revealNever  revealMode = "never"  // live to the admin, never to participants
+)
+
+// revealMode is the reveal mode of a poll.
+type revealMode string
+
+// adminLive returns whether the admin sees the results before closing.
+func (r revealMode) adminLive() bool {
+	return r != revealHidden
+}
+
+// userLive returns whether participants see the results before closing.
+func (r revealMode) userLive() bool {
+	return r == revealLive
+}
+
+// userFinal returns whether participants see the results after closing.
+func (r revealMode) userFinal() bool {
+	return r != revealNever
+}
+
+// parseRevealMode returns the reveal mode of the configuration.
+func parseRevealMode(input map[string]string) (revealMode, error) {
+	r := revealMode(input[choiceRevealKey])
+	switch r {
+	case revealAdmin, revealLive, revealHidden, revealNever:
+		return r, nil
+	}
+	return revealAdmin, fmt.Errorf("unknown reveal mode %s", r)
+}
+
+const choiceClosed = `
+<h1>{{.Question}}</h1>
+<p><em>{{.Translation.ResultsHidden}}</em></p>
+`
+
+var choiceClosedTemplate = template.Must(template.New("choiceClosed").Parse(choiceClosed))
+
+type choiceClosedStruct struct {
+	Question    string
+	Translation translation.Translation
+}
+
+// choiceClosedPage returns the page shown to participants after closing if the results are never revealed to them.
+func choiceClosedPage(question string) template.HTML {
+	td := choiceClosedStruct{
+		Question:    question,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := choiceClosedTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing choiceClosed: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+// choiceConfig is the parsed configuration of the Question and MultipleChoice plugins.
+type choiceConfig struct {
+	Question  string
+	Options   []choiceOption
+	TimeLimit time.Duration
+	Reveal    revealMode
+}
+
+// parseChoiceConfig parses the configuration of the Question and MultipleChoice plugins.
+// The configuration is a JSON object containing the question ("q"), the options as an array ("o"), the optional time limit ("tl") and the optional reveal mode ("r").
+// For backwards compatibility, options can also be given as numbered keys ("1", "2", ...) if "o" is not present.
+// Empty options are ignored.
+func parseChoiceConfig(b []byte) (choiceConfig, error) {
+	c := choiceConfig{}
+
+	raw := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return c, err
+	}
+	input := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if k == choiceOptionsKey {
+			continue
+		}
+		var s string
+		err = json.Unmarshal(v, &s)
+		if err != nil {
+			return c, fmt.Errorf("can not parse %s: %w", k, err)
+		}
+		input[k] = s
+	}
+
+	c.Question = input["q"]
+	if c.Question == "" {
+		return c, fmt.Errorf("no question found")
+	}
+
+	options := make([]choiceOption, 0)
+	if o, ok := raw[choiceOptionsKey]; ok {
+		err = json.Unmarshal(o, &options)
+		if err != nil {
+			return c, fmt.Errorf("can not parse options: %w", err)
+		}
+	} else {
+		keys := make([]int, 0, len(input))
+		for k := range input {
+			i, err := strconv.Atoi(k)
+			if err == nil && i > 0 {
+				keys = append(keys, i)
+			}
+		}
+		sort.Ints(keys)
+		for _, k := range keys {
+			options = append(options, choiceOption{Text: input[strconv.Itoa(k)]})
+		}
+	}
+	for i := range options {
+		if strings.TrimSpace(options[i].Text) != "" {
+			c.Options = append(c.Options, options[i])
+		}
+	}
+	if len(c.Options) == 0 {
+		return c, fmt.Errorf("no answers found")
+	}
+
+	c.TimeLimit, err = parseTimeLimit(input)
+	if err != nil {
+		return c, err
+	}
+	c.Reveal, err = parseRevealMode(input)
+	if err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// choiceLiveScript updates the number of answers shown to participants if the results are revealed live.
+const choiceLiveScript = `
+{{if .Live}}
+<script>
+data_function = function(b) {
+	let counts = JSON.parse(b);
+	for(let i = 0; i < counts.length; i++) {
+		let e = document.getElementById("Question_count_" + i);
+		if(e !== null) {
+			e.textContent = counts[i];
+		}
+	}
+};
+</script>
+{{end}}
+`
+
+const choiceConfigScript = `
+<script>
+function choiceAddOption(prefix, text, markdown) {
+	let li = document.createElement("LI");
+	let input = document.createElement("INPUT");
+	input.type = "text";
+	input.value = text;
+	input.classList.add("ChoiceOptionText");
+	li.appendChild(input);
+	let label = document.createElement("LABEL");
+	let checkbox = document.createElement("INPUT");
+	checkbox.type = "checkbox";
+	checkbox.checked = markdown;
+	checkbox.classList.add("ChoiceOptionMarkdown");
+	label.appendChild(checkbox);
+	label.appendChild(document.createTextNode(" {{.Translation.Markdown}} "));
+	li.appendChild(label);
+	let remove = document.createElement("BUTTON");
+	remove.textContent = "{{.Translation.Remove}}";
+	remove.onclick = function() {
+		li.remove();
+	};
+	li.appendChild(remove);
+	document.getElementById(prefix + "_options").appendChild(li);
+}
+
+function choiceGetData(prefix) {
+	let options = [];
+	document.querySelectorAll("#" + prefix + "_options li").forEach(function(li) {
+		let text = li.querySelector(".ChoiceOptionText").value;
+		if(text.trim() !== "") {
+			let markdown = li.querySelector(".ChoiceOptionMarkdown").checked;
+			options.push(markdown ? {"t": text, "md": true} : text);
+		}
+	});
+	return JSON.stringify({"q": document.getElementById(prefix).value, "o": options, "tl": document.getElementById(prefix + "_tl").value, "r": document.getElementById(prefix + "_r").value});
+}
+</script>
+`
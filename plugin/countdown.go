@@ -0,0 +1,330 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/responsego/registry"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+func init() {
+	err := registry.RegisterFeedbackPlugin(func() registry.FeedbackPlugin { return new(countdown) }, "Countdown")
+	if err != nil {
+		panic(err)
+	}
+}
+
+const countdownConfig = `
+<h1>{{.Translation.DisplayCountdown}}</h1>
+<label for="CountdownTitle">{{.Translation.Title}}:</label> <input id="CountdownTitle" type="text"><br>
+<label for="CountdownSeconds">{{.Translation.Seconds}}:</label> <input id="CountdownSeconds" type="number" min="1" value="30"><br>
+<input id="CountdownAutostart" type="checkbox" checked> <label for="CountdownAutostart">{{.Translation.StartImmediately}}</label><br>
+<p><button onclick="sendActivate('Countdown', countdownGetData())">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('Countdown', countdownGetData(), '{{.Translation.DisplayCountdown}}: '+document.getElementById('CountdownTitle').value.substring(0,80)+(document.getElementById('CountdownTitle').value.length>80?'[...]':''))">{{.Translation.SaveElement}}</button></p>
+
+<script>
+function countdownGetData() {
+	let data = {"Title": document.getElementById('CountdownTitle').value, "Seconds": parseInt(document.getElementById('CountdownSeconds').value), "Autostart": document.getElementById('CountdownAutostart').checked};
+	return JSON.stringify(data);
+}
+</script>
+`
+
+var countdownConfigTemplate = template.Must(template.New("countdownConfig").Parse(countdownConfig))
+
+type countdownConfigStruct struct {
+	Translation translation.Translation
+}
+
+const countdownHTML = `
+<h1>{{.Title}}</h1>
+<p id="countdownDisplay" class="countdown"></p>
+<p id="countdownDone" class="centre hidden"><strong>{{.Translation.TimeIsUp}}</strong></p>
+{{if .Admin}}
+<p class="centre">
+<button onclick="sendData('Countdown', 'start')">{{.Translation.Start}}</button>
+<button onclick="sendData('Countdown', 'pause')">{{.Translation.Pause}}</button>
+<button onclick="sendData('Countdown', 'reset')">{{.Translation.Reset}}</button>
+</p>
+{{end}}
+
+<script>
+var countdownRemaining = {{.Remaining}};
+var countdownRunning = {{.Running}};
+var countdownUpdated = Date.now();
+
+function countdownShow() {
+	var e = document.getElementById("countdownDisplay");
+	if(e === null) {
+		return false;
+	}
+	var r = countdownRemaining;
+	if(countdownRunning) {
+		r = r - (Date.now() - countdownUpdated);
+	}
+	r = Math.max(0, Math.ceil(r / 1000));
+	e.textContent = Math.floor(r / 60) + ":" + String(r % 60).padStart(2, "0");
+	if(r === 0) {
+		document.getElementById("countdownDone").classList.remove("hidden");
+	} else {
+		document.getElementById("countdownDone").classList.add("hidden");
+	}
+	return true;
+}
+
+data_function = function(b) {
+	try {
+		var data = JSON.parse(b);
+		countdownRemaining = data.Remaining;
+		countdownRunning = data.Running;
+		countdownUpdated = Date.now();
+		countdownShow();
+	} catch (e) {
+		console.log(e);
+	}
+};
+
+if(typeof countdownInterval !== "undefined") {
+	clearInterval(countdownInterval);
+}
+countdownShow();
+var countdownInterval = setInterval(function() {
+	if(!countdownShow()) {
+		clearInterval(countdownInterval);
+	}
+}, 200);
+</script>
+`
+
+var countdownHTMLTemplate = template.Must(template.New("countdownHTML").Parse(countdownHTML))
+
+type countdownHTMLStruct struct {
+	Title       string
+	Admin       bool
+	Remaining   int64
+	Running     bool
+	Translation translation.Translation
+}
+
+type countdownGetConfig struct {
+	Title     string
+	Seconds   int
+	Autostart bool
+}
+
+type countdownUpdate struct {
+	Remaining int64
+	Running   bool
+}
+
+type countdown struct {
+	adminHTML  chan<- template.HTML
+	userHTML   chan<- template.HTML
+	adminInput <-chan []byte
+	userInput  <-chan []byte
+	adminData  chan<- []byte
+	userData   chan<- []byte
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	title     string
+	duration  time.Duration
+	remaining time.Duration // only valid while not running
+	end       time.Time     // only valid while running
+	running   bool
+
+	l sync.Mutex
+}
+
+func (c *countdown) ConfigHTML() (string, template.HTML) {
+	tl := translation.GetDefaultTranslation()
+	td := countdownConfigStruct{
+		Translation: tl,
+	}
+	var buf bytes.Buffer
+	err := countdownConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing countdown config: %s", err.Error())
+	}
+
+	return tl.DisplayCountdown, template.HTML(buf.Bytes())
+}
+
+func (c *countdown) UserHTMLChannel(ch chan<- template.HTML) {
+	c.userHTML = ch
+}
+
+func (c *countdown) AdminHTMLChannel(ch chan<- template.HTML) {
+	c.adminHTML = ch
+}
+
+func (c *countdown) ReceiveUserChannel(ch <-chan []byte) {
+	c.userInput = ch
+}
+
+func (c *countdown) ReceiveAdminChannel(ch <-chan []byte) {
+	c.adminInput = ch
+}
+
+func (c *countdown) AdminDataChannel(ch chan<- []byte) {
+	c.adminData = ch
+}
+
+func (c *countdown) UserDataChannel(ch chan<- []byte) {
+	c.userData = ch
+}
+
+func (c *countdown) Activate(b []byte) error {
+	var config countdownGetConfig
+	err := json.Unmarshal(b, &config)
+	if err != nil {
+		return err
+	}
+
+	if config.Seconds <= 0 {
+		return errors.New("countdown: seconds must be positive")
+	}
+
+	c.title = config.Title
+	c.duration = time.Duration(config.Seconds) * time.Second
+	c.remaining = c.duration
+	if config.Autostart {
+		c.running = true
+		c.end = time.Now().Add(c.duration)
+	}
+
+	go func() { c.userHTML <- c.getHTML(false) }()
+	go func() { c.adminHTML <- c.getHTML(true) }()
+	c.ctx = context.Background()
+	c.ctx, c.cancel = context.WithCancel(c.ctx)
+	go c.worker(c.ctx)
+
+	return nil
+}
+
+func (c *countdown) GetLastHTMLUser() template.HTML {
+	return c.getHTML(false)
+}
+
+func (c *countdown) GetLastHTMLAdmin() template.HTML {
+	return c.getHTML(true)
+}
+
+func (c *countdown) Deactivate() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// state returns the current remaining time and whether the countdown is running.
+// It stops the countdown once the time is up. Caller must hold l.
+func (c *countdown) state() (time.Duration, bool) {
+	if !c.running {
+		return c.remaining, false
+	}
+	r := time.Until(c.end)
+	if r <= 0 {
+		c.running = false
+		c.remaining = 0
+		return 0, false
+	}
+	return r, true
+}
+
+func (c *countdown) getHTML(admin bool) template.HTML {
+	c.l.Lock()
+	r, running := c.state()
+	c.l.Unlock()
+
+	td := countdownHTMLStruct{
+		Title:       c.title,
+		Admin:       admin,
+		Remaining:   r.Milliseconds(),
+		Running:     running,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := countdownHTMLTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing countdown: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (c *countdown) worker(ctx context.Context) {
+	done := ctx.Done()
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	for {
+		select {
+		case b := <-c.adminInput:
+			c.l.Lock()
+			r, running := c.state()
+			switch string(b) {
+			case "start":
+				if !running && r > 0 {
+					c.running = true
+					c.end = time.Now().Add(r)
+				}
+			case "pause":
+				if running {
+					c.running = false
+					c.remaining = r
+				}
+			case "reset":
+				c.running = false
+				c.remaining = c.duration
+			}
+			c.l.Unlock()
+			c.sendUpdate()
+		case <-c.userInput:
+			// Participants can not influence the countdown
+		case <-t.C:
+			c.l.Lock()
+			wasRunning := c.running
+			c.l.Unlock()
+			if wasRunning {
+				c.sendUpdate()
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// sendUpdate sends the authoritative state of the countdown to all participants and admins.
+func (c *countdown) sendUpdate() {
+	c.l.Lock()
+	r, running := c.state()
+	c.l.Unlock()
+
+	b, err := json.Marshal(countdownUpdate{Remaining: r.Milliseconds(), Running: running})
+	if err != nil {
+		log.Printf("countdown: Error marshaling update: (%s)", err.Error())
+		return
+	}
+	c.adminData <- b
+	c.userData <- b
+}
@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020,2025 Marcus Soll
+// Copyright 2020,2025,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -22,11 +22,16 @@ import (
 	"fmt"
 	"html/template"
 	"log"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/Top-Ranger/responsego/registry"
 	"github.com/Top-Ranger/responsego/translation"
+	"github.com/Top-Ranger/responsego/wordlist"
 )
 
 func init() {
@@ -46,44 +51,165 @@ const freetextConfig = `
 const freetextUser = `
 <h1>{{.Question}}</h1>
 <textarea id="FreeText"></textarea>
-<p><button onclick="sendData('FreeText', document.getElementById('FreeText').value); document.getElementById('FreeText').value = ''">{{.Translation.Submit}}</button></p>
+<p><button onclick="freetextSubmit()">{{.Translation.Submit}}</button></p>
+<div id="freetextPublished"></div>
+
+<script>
+var freetextLiked = {};
+
+function freetextSubmit() {
+	sendData('FreeText', JSON.stringify({"Answer": document.getElementById('FreeText').value}));
+	document.getElementById('FreeText').value = '';
+}
+
+function freetextShowPublished(published) {
+	let d = document.getElementById("freetextPublished");
+	if(d === null) {
+		return;
+	}
+	d.innerHTML = "";
+	if(published.length === 0) {
+		return;
+	}
+	let h = document.createElement("H2");
+	h.textContent = "{{.Translation.PublishedAnswers}}";
+	d.appendChild(h);
+	let ul = document.createElement("UL");
+	for(let i = 0; i < published.length; i++) {
+		let li = document.createElement("LI");
+		li.textContent = published[i].Text + " ";
+		let button = document.createElement("BUTTON");
+		button.textContent = "♥ " + published[i].Likes;
+		button.title = "{{.Translation.Like}}";
+		button.disabled = freetextLiked[published[i].ID] === true;
+		button.onclick = function() {
+			sendData('FreeText', JSON.stringify({"Like": published[i].ID}));
+			freetextLiked[published[i].ID] = true;
+			button.disabled = true;
+		};
+		li.appendChild(button);
+		ul.appendChild(li);
+	}
+	d.appendChild(ul);
+}
+
+data_function = function(b) {
+	try {
+		freetextShowPublished(JSON.parse(b));
+	} catch (e) {
+		console.log(e);
+	}
+};
+
+freetextShowPublished({{.Published}});
+</script>
 `
 
 var freetextUserTemplate = template.Must(template.New("freetextUser").Parse(freetextUser))
 
 type freetextUserStruct struct {
 	Question    string
+	Published   []freetextPublished
 	Translation translation.Translation
 }
 
 const freetextAdmin = `
 <h1>{{.Question}}</h1>
 <p>{{.Translation.UpdateAll5Seconds}}</p>
+<p>
+<button onclick="freetextCommand('publishstarred', -1)">{{.Translation.PublishStarred}}</button>
+<button onclick="freetextCommand('unpublishall', -1)">{{.Translation.UnpublishAll}}</button>
+<button onclick="freetextCommand('group', -1)">{{if .Grouped}}{{.Translation.Ungroup}}{{else}}{{.Translation.GroupByKeywords}}{{end}}</button>
+</p>
+{{range $g := .Groups}}
+{{if $g.Keywords}}<h2>{{$g.Keywords}}</h2>{{else if $.Grouped}}<h2>{{$.Translation.Other}}</h2>{{end}}
+<ul>
+{{range $i, $e := $g.Answers}}
+<li{{if $e.Starred}} class="highlight"{{end}}>{{$e.Text}}
+{{if $e.Published}}<em>({{$.Translation.Published}}, &#x2665; {{$e.Likes}})</em>{{end}}
+<button onclick="freetextCommand('star', {{$e.ID}})">{{if $e.Starred}}{{$.Translation.Unstar}}{{else}}{{$.Translation.Star}}{{end}}</button>
+<button onclick="freetextCommand('publish', {{$e.ID}})">{{if $e.Published}}{{$.Translation.Unpublish}}{{else}}{{$.Translation.Publish}}{{end}}</button>
+<button onclick="freetextCommand('hide', {{$e.ID}})">{{$.Translation.Hide}}</button>
+</li>
+{{end}}
+</ul>
+{{end}}
+{{if .Hidden}}
+<details>
+<summary>{{.Translation.Hidden}} ({{len .Hidden}})</summary>
 <ul>
-{{range $i, $e := .Answers}}
-<li>{{$e}}</li>
+{{range $i, $e := .Hidden}}
+<li>{{$e.Text}} <button onclick="freetextCommand('hide', {{$e.ID}})">{{$.Translation.Unhide}}</button></li>
 {{end}}
 </ul>
+</details>
+{{end}}
+
+<script>
+function freetextCommand(action, id) {
+	sendData('FreeText', JSON.stringify({"Action": action, "ID": id}));
+}
+</script>
 `
 
 var freetextAdminTemplate = template.Must(template.New("questionAdmin").Parse(freetextAdmin))
 
 type freetextAdminStruct struct {
 	Question    string
-	Answers     []string
+	Grouped     bool
+	Groups      []freetextGroup
+	Hidden      []*freetextAnswer
 	Translation translation.Translation
 }
 
+// freetextMinKeywordLength is the minimal length in runes of a word to be considered as a keyword.
+const freetextMinKeywordLength = 3
+
+type freetextAnswer struct {
+	ID        int
+	Text      string
+	Starred   bool
+	Hidden    bool
+	Published bool
+	Likes     int
+	likedBy   map[string]bool
+}
+
+type freetextGroup struct {
+	Keywords string
+	Answers  []*freetextAnswer
+}
+
+type freetextPublished struct {
+	ID    int
+	Text  string
+	Likes int
+}
+
+type freetextUserInput struct {
+	Answer *string
+	Like   *int
+}
+
+type freetextCommand struct {
+	Action string
+	ID     int
+}
+
 type freetext struct {
-	adminHTML  chan<- template.HTML
-	userHTML   chan<- template.HTML
-	adminInput <-chan []byte
-	userInput  <-chan []byte
-	ctx        context.Context
-	cancel     context.CancelFunc
+	adminHTML        chan<- template.HTML
+	userHTML         chan<- template.HTML
+	adminInput       <-chan []byte
+	userInput        <-chan []byte
+	participantInput <-chan registry.ParticipantInput
+	adminData        chan<- []byte
+	userData         chan<- []byte
+	ctx              context.Context
+	cancel           context.CancelFunc
 
 	Question      string
-	Answers       []string
+	Answers       []*freetextAnswer
+	Grouped       bool
 	NumberChanged bool
 	AnswerLock    sync.Mutex
 }
@@ -105,23 +231,26 @@ func (f *freetext) ReceiveUserChannel(c <-chan []byte) {
 	f.userInput = c
 }
 
+func (f *freetext) ReceiveUserParticipantChannel(c <-chan registry.ParticipantInput) {
+	f.participantInput = c
+}
+
 func (f *freetext) ReceiveAdminChannel(c <-chan []byte) {
 	f.adminInput = c
 }
 
+func (f *freetext) AdminDataChannel(c chan<- []byte) {
+	f.adminData = c
+}
+
+func (f *freetext) UserDataChannel(c chan<- []byte) {
+	f.userData = c
+}
+
 func (f *freetext) Activate(b []byte) error {
 	f.Question = string(b)
 	go func() {
-		td := freetextUserStruct{
-			Question:    f.Question,
-			Translation: translation.GetDefaultTranslation(),
-		}
-		var buf bytes.Buffer
-		err := freetextUserTemplate.Execute(&buf, td)
-		if err != nil {
-			log.Printf("error executing freetextUser: %s", err.Error())
-		}
-		f.userHTML <- template.HTML(buf.Bytes())
+		f.userHTML <- f.GetLastHTMLUser()
 	}()
 	go func() {
 		f.adminHTML <- f.getAdminPage()
@@ -135,12 +264,24 @@ func (f *freetext) Activate(b []byte) error {
 		defer ticker.Stop()
 		for {
 			select {
-			case <-f.adminInput:
-			case b := <-f.userInput:
+			case b := <-f.adminInput:
+				var c freetextCommand
+				err := json.Unmarshal(b, &c)
+				if err != nil {
+					continue
+				}
 				f.AnswerLock.Lock()
-				f.Answers = append(f.Answers, string(b))
-				f.NumberChanged = true
+				f.moderate(c)
+				f.NumberChanged = false
 				f.AnswerLock.Unlock()
+				f.adminHTML <- f.getAdminPage()
+				f.sendPublished()
+
+			case b := <-f.userInput:
+				f.receive(registry.ParticipantInput{Data: b})
+
+			case p := <-f.participantInput:
+				f.receive(p)
 
 			case <-ticker.C:
 				f.AnswerLock.Lock()
@@ -149,6 +290,7 @@ func (f *freetext) Activate(b []byte) error {
 				f.AnswerLock.Unlock()
 				if changed {
 					f.adminHTML <- f.getAdminPage()
+					f.sendPublished()
 				}
 			case <-done:
 				return
@@ -158,12 +300,106 @@ func (f *freetext) Activate(b []byte) error {
 	return nil
 }
 
+// receive handles an input of a participant, which is either an answer or a like of a published answer.
+func (f *freetext) receive(p registry.ParticipantInput) {
+	var input freetextUserInput
+	err := json.Unmarshal(p.Data, &input)
+	if err != nil {
+		return
+	}
+
+	f.AnswerLock.Lock()
+	defer f.AnswerLock.Unlock()
+
+	switch {
+	case input.Answer != nil:
+		if strings.TrimSpace(*input.Answer) == "" {
+			return
+		}
+		f.Answers = append(f.Answers, &freetextAnswer{ID: len(f.Answers), Text: *input.Answer, likedBy: make(map[string]bool)})
+		f.NumberChanged = true
+	case input.Like != nil:
+		if *input.Like < 0 || *input.Like >= len(f.Answers) {
+			return
+		}
+		a := f.Answers[*input.Like]
+		if !a.Published || a.likedBy[p.Participant] {
+			return
+		}
+		a.likedBy[p.Participant] = true
+		a.Likes++
+		f.NumberChanged = true
+	}
+}
+
+// moderate applies a command of an admin. Caller must hold AnswerLock.
+func (f *freetext) moderate(c freetextCommand) {
+	var a *freetextAnswer
+	if c.ID >= 0 && c.ID < len(f.Answers) {
+		a = f.Answers[c.ID]
+	}
+
+	switch c.Action {
+	case "star":
+		if a != nil {
+			a.Starred = !a.Starred
+		}
+	case "hide":
+		if a != nil {
+			a.Hidden = !a.Hidden
+			a.Published = a.Published && !a.Hidden
+		}
+	case "publish":
+		if a != nil && !a.Hidden {
+			a.Published = !a.Published
+		}
+	case "publishstarred":
+		for i := range f.Answers {
+			if f.Answers[i].Starred && !f.Answers[i].Hidden {
+				f.Answers[i].Published = true
+			}
+		}
+	case "unpublishall":
+		for i := range f.Answers {
+			f.Answers[i].Published = false
+		}
+	case "group":
+		f.Grouped = !f.Grouped
+	}
+}
+
+// published returns all published answers. Caller must hold AnswerLock.
+func (f *freetext) published() []freetextPublished {
+	p := make([]freetextPublished, 0)
+	for i := range f.Answers {
+		if f.Answers[i].Published {
+			p = append(p, freetextPublished{ID: f.Answers[i].ID, Text: f.Answers[i].Text, Likes: f.Answers[i].Likes})
+		}
+	}
+	return p
+}
+
+// sendPublished sends all published answers to the participants.
+func (f *freetext) sendPublished() {
+	f.AnswerLock.Lock()
+	p := f.published()
+	f.AnswerLock.Unlock()
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		log.Printf("freetext: Error marshaling published answers: (%s)", err.Error())
+		return
+	}
+	f.userData <- b
+}
+
 func (f *freetext) GetLastHTMLUser() template.HTML {
 	f.AnswerLock.Lock()
 	defer f.AnswerLock.Unlock()
 
 	td := freetextUserStruct{
 		Question:    f.Question,
+		Published:   f.published(),
 		Translation: translation.GetDefaultTranslation(),
 	}
 	var buf bytes.Buffer
@@ -184,16 +420,125 @@ func (f *freetext) Deactivate() {
 	}
 }
 
+// freetextKeywords returns all keywords of an answer.
+// Keywords are lower case words with a minimum length which are not stop-words.
+func freetextKeywords(text string) map[string]bool {
+	language := translation.GetDefaultTranslation().Language
+	k := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsNumber(r) })
+	for i := range words {
+		w := words[i]
+		if utf8.RuneCountInString(w) < freetextMinKeywordLength || wordlist.IsStopWord(language, w) {
+			continue
+		}
+		k[w] = true
+	}
+	return k
+}
+
+// groupAnswers clusters answers sharing at least one keyword.
+// Answers without any shared keyword are returned in a last group without keywords.
+func groupAnswers(answers []*freetextAnswer) []freetextGroup {
+	parent := make([]int, len(answers))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	keywords := make([]map[string]bool, len(answers))
+	firstSeen := make(map[string]int)
+	for i := range answers {
+		keywords[i] = freetextKeywords(answers[i].Text)
+		for k := range keywords[i] {
+			if j, ok := firstSeen[k]; ok {
+				parent[find(i)] = find(j)
+			} else {
+				firstSeen[k] = i
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	order := make([]int, 0)
+	for i := range answers {
+		r := find(i)
+		if _, ok := members[r]; !ok {
+			order = append(order, r)
+		}
+		members[r] = append(members[r], i)
+	}
+
+	groups := make([]freetextGroup, 0, len(order))
+	other := freetextGroup{}
+	for _, r := range order {
+		if len(members[r]) == 1 {
+			other.Answers = append(other.Answers, answers[members[r][0]])
+			continue
+		}
+		count := make(map[string]int)
+		for _, i := range members[r] {
+			for k := range keywords[i] {
+				count[k]++
+			}
+		}
+		shared := make([]string, 0)
+		for k, v := range count {
+			if v > 1 {
+				shared = append(shared, k)
+			}
+		}
+		sort.Slice(shared, func(i, j int) bool {
+			if count[shared[i]] != count[shared[j]] {
+				return count[shared[i]] > count[shared[j]]
+			}
+			return shared[i] < shared[j]
+		})
+		if len(shared) > 3 {
+			shared = shared[:3]
+		}
+		g := freetextGroup{Keywords: strings.Join(shared, ", ")}
+		for _, i := range members[r] {
+			g.Answers = append(g.Answers, answers[i])
+		}
+		groups = append(groups, g)
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i].Answers) > len(groups[j].Answers) })
+	if len(other.Answers) > 0 {
+		groups = append(groups, other)
+	}
+	return groups
+}
+
 func (f *freetext) getAdminPage() template.HTML {
 	f.AnswerLock.Lock()
 	defer f.AnswerLock.Unlock()
 
 	td := freetextAdminStruct{
 		Question:    f.Question,
-		Answers:     f.Answers,
+		Grouped:     f.Grouped,
 		Translation: translation.GetDefaultTranslation(),
 	}
 
+	visible := make([]*freetextAnswer, 0, len(f.Answers))
+	for i := range f.Answers {
+		if f.Answers[i].Hidden {
+			td.Hidden = append(td.Hidden, f.Answers[i])
+			continue
+		}
+		visible = append(visible, f.Answers[i])
+	}
+	if f.Grouped {
+		td.Groups = groupAnswers(visible)
+	} else {
+		td.Groups = []freetextGroup{{Answers: visible}}
+	}
+
 	var buf bytes.Buffer
 	err := freetextAdminTemplate.Execute(&buf, td)
 	if err != nil {
@@ -202,11 +547,44 @@ func (f *freetext) getAdminPage() template.HTML {
 	return template.HTML(buf.Bytes())
 }
 
+type freetextResultStruct struct {
+	Text      string
+	Starred   bool
+	Hidden    bool
+	Published bool
+	Likes     int
+	Group     string
+}
+
 func (f *freetext) GetAdminDownload() []byte {
 	f.AnswerLock.Lock()
 	defer f.AnswerLock.Unlock()
 
-	b, err := json.Marshal(f.Answers)
+	r := make([]freetextResultStruct, len(f.Answers))
+	for i := range f.Answers {
+		r[i] = freetextResultStruct{
+			Text:      f.Answers[i].Text,
+			Starred:   f.Answers[i].Starred,
+			Hidden:    f.Answers[i].Hidden,
+			Published: f.Answers[i].Published,
+			Likes:     f.Answers[i].Likes,
+		}
+	}
+
+	visible := make([]*freetextAnswer, 0, len(f.Answers))
+	for i := range f.Answers {
+		if !f.Answers[i].Hidden {
+			visible = append(visible, f.Answers[i])
+		}
+	}
+	groups := groupAnswers(visible)
+	for i := range groups {
+		for _, a := range groups[i].Answers {
+			r[a.ID].Group = groups[i].Keywords
+		}
+	}
+
+	b, err := json.Marshal(r)
 	if err != nil {
 		return []byte(err.Error())
 	}
@@ -0,0 +1,638 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/responsego/helper"
+	"github.com/Top-Ranger/responsego/registry"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+// hotspotClusterRadius is the maximum distance (in normalised coordinates) of a point to the centre of its cluster.
+const (
+	hotspotClusterRadius = 0.05
+	hotspotDefaultPoints = 1
+	hotspotMaxPoints     = 100
+)
+
+func init() {
+	err := registry.RegisterFeedbackPlugin(func() registry.FeedbackPlugin { return new(hotspot) }, "Hotspot")
+	if err != nil {
+		panic(err)
+	}
+}
+
+const hotspotConfig = `
+<h1>{{.Translation.DisplayHotspot}}</h1>
+<p>{{.Translation.DisplayQuestion}}: <input id="HotspotQuestion" type="text"></p>
+<p><input id="HotspotFile" type="file" accept="image/png,image/jpeg,image/gif,image/webp"> <button onclick="hotspotUpload()">{{.Translation.Upload}}</button></p>
+<p><img id="HotspotPreview" class="icon hidden" alt="" src=""></p>
+<p><label for="HotspotPoints">{{.Translation.PointsPerParticipant}}:</label> <input id="HotspotPoints" type="number" min="1" max="{{.MaxPoints}}" value="{{.DefaultPoints}}"></p>
+<p><button onclick="sendActivate('Hotspot', hotspotGetData())">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('Hotspot', hotspotGetData(), '{{.Translation.DisplayHotspot}}: '+document.getElementById('HotspotQuestion').value)">{{.Translation.SaveElement}}</button></p>
+
+<script>
+var hotspotImage = "";
+
+function hotspotUpload() {
+	let files = document.getElementById('HotspotFile').files;
+	if(files.length === 0) {
+		return;
+	}
+	uploadImage(files[0], function(url) {
+		if(url !== null) {
+			hotspotImage = url;
+			let preview = document.getElementById('HotspotPreview');
+			preview.src = url;
+			preview.classList.remove("hidden");
+		}
+	});
+	document.getElementById('HotspotFile').value = "";
+}
+
+function hotspotGetData() {
+	let data = {"Question": document.getElementById('HotspotQuestion').value, "Image": hotspotImage, "Points": parseInt(document.getElementById('HotspotPoints').value)};
+	return JSON.stringify(data);
+}
+</script>
+`
+
+var hotspotConfigTemplate = template.Must(template.New("hotspotConfig").Parse(hotspotConfig))
+
+type hotspotConfigStruct struct {
+	DefaultPoints int
+	MaxPoints     int
+	Translation   translation.Translation
+}
+
+const hotspotUser = `
+<h1>{{.Question}}</h1>
+<p>{{.Translation.ClickOnImage}}</p>
+<div class="centre">
+<div class="hotspot">
+	<img id="hotspotImage" src="{{.Image}}" alt="{{.Question}}" onclick="hotspotClick(event)">
+	<div id="hotspotMarker" class="hotspotmarker hidden"></div>
+</div>
+</div>
+<p><button id="hotspotSubmit" onclick="hotspotSubmit()" disabled>{{.Translation.Submit}}</button>{{if gt .Points 1}} <strong>{{.Translation.RemainingPoints}}: <span id="hotspotRemaining">{{.Points}}</span></strong>{{end}}</p>
+
+<script>
+var hotspotSelected = null;
+var hotspotRemaining = {{.Points}};
+
+function hotspotClick(e) {
+	if(hotspotRemaining <= 0) {
+		return;
+	}
+	let rect = e.target.getBoundingClientRect();
+	hotspotSelected = {"X": (e.clientX - rect.left) / rect.width, "Y": (e.clientY - rect.top) / rect.height};
+	let marker = document.getElementById("hotspotMarker");
+	marker.style.left = (hotspotSelected.X * 100) + "%";
+	marker.style.top = (hotspotSelected.Y * 100) + "%";
+	marker.classList.remove("hidden");
+	document.getElementById("hotspotSubmit").disabled = false;
+}
+
+function hotspotSubmit() {
+	if(hotspotSelected === null) {
+		return;
+	}
+	sendData('Hotspot', JSON.stringify(hotspotSelected));
+	hotspotSelected = null;
+	hotspotRemaining--;
+	document.getElementById("hotspotSubmit").disabled = true;
+	let remaining = document.getElementById("hotspotRemaining");
+	if(remaining !== null) {
+		remaining.textContent = hotspotRemaining;
+	}
+	if(hotspotRemaining <= 0) {
+		document.getElementById("hotspotImage").onclick = null;
+	}
+}
+</script>
+`
+
+var hotspotUserTemplate = template.Must(template.New("hotspotUser").Parse(hotspotUser))
+
+type hotspotUserStruct struct {
+	Question    string
+	Image       string
+	Points      int
+	Translation translation.Translation
+}
+
+const hotspotAdmin = `
+<h1>{{.Question}}</h1>
+<div class="centre">
+<div class="hotspot">
+	<img id="hotspotImage" src="{{.Image}}" alt="{{.Question}}">
+	<canvas id="hotspotCanvas"></canvas>
+</div>
+</div>
+<p><input id="hotspotShowPoints" type="checkbox" onchange="hotspotDraw()" {{if not .Finished}}checked{{end}}> <label for="hotspotShowPoints">{{.Translation.ShowPoints}}</label></p>
+{{if .Finished}}
+<p><em>{{.Translation.Submitted}}: {{len .Points}}</em></p>
+{{if .Clusters}}
+<h2>{{.Translation.Clusters}}</h2>
+<ol>
+{{range $i, $e := .Clusters}}
+<li>{{$e.Count}} ({{percent $e.Count $.Points}})</li>
+{{end}}
+</ol>
+{{end}}
+{{else}}
+<p><em>{{.Translation.Submitted}}: <span id="hotspotSubmitted">{{len .Points}}</span></em></p>
+<p><button onclick="sendData('Hotspot', 'close')">{{.Translation.Finish}}</button></p>
+{{end}}
+
+<script>
+var hotspotPoints = {{.Points}};
+var hotspotClusters = {{.Clusters}};
+
+function hotspotDraw() {
+	let c = document.getElementById("hotspotCanvas");
+	let img = document.getElementById("hotspotImage");
+	if(c === null || img === null || !img.complete || img.naturalWidth === 0) {
+		return;
+	}
+	let w = 400;
+	let h = Math.max(1, Math.round(w * img.naturalHeight / img.naturalWidth));
+	c.width = w;
+	c.height = h;
+	let ctx = c.getContext("2d");
+	ctx.clearRect(0, 0, w, h);
+	let r = Math.max(w, h) * 0.04;
+
+	if(hotspotPoints.length > 0) {
+		for(let i = 0; i < hotspotPoints.length; i++) {
+			let x = hotspotPoints[i].X * w;
+			let y = hotspotPoints[i].Y * h;
+			let g = ctx.createRadialGradient(x, y, 0, x, y, r);
+			g.addColorStop(0, "rgba(0,0,0,0.25)");
+			g.addColorStop(1, "rgba(0,0,0,0)");
+			ctx.fillStyle = g;
+			ctx.fillRect(x - r, y - r, 2 * r, 2 * r);
+		}
+
+		let palette = document.createElement("canvas");
+		palette.width = 256;
+		palette.height = 1;
+		let pctx = palette.getContext("2d");
+		let pg = pctx.createLinearGradient(0, 0, 256, 0);
+		pg.addColorStop(0, "blue");
+		pg.addColorStop(0.25, "cyan");
+		pg.addColorStop(0.5, "lime");
+		pg.addColorStop(0.75, "yellow");
+		pg.addColorStop(1, "red");
+		pctx.fillStyle = pg;
+		pctx.fillRect(0, 0, 256, 1);
+		let colours = pctx.getImageData(0, 0, 256, 1).data;
+
+		let d = ctx.getImageData(0, 0, w, h);
+		let max = 1;
+		for(let i = 3; i < d.data.length; i += 4) {
+			max = Math.max(max, d.data[i]);
+		}
+		for(let i = 3; i < d.data.length; i += 4) {
+			let a = d.data[i];
+			if(a === 0) {
+				continue;
+			}
+			let index = Math.min(255, Math.floor(a / max * 255)) * 4;
+			d.data[i - 3] = colours[index];
+			d.data[i - 2] = colours[index + 1];
+			d.data[i - 1] = colours[index + 2];
+			d.data[i] = Math.min(200, 60 + Math.floor(a / max * 140));
+		}
+		ctx.putImageData(d, 0, 0);
+	}
+
+	if(document.getElementById("hotspotShowPoints").checked) {
+		ctx.fillStyle = "black";
+		for(let i = 0; i < hotspotPoints.length; i++) {
+			ctx.beginPath();
+			ctx.arc(hotspotPoints[i].X * w, hotspotPoints[i].Y * h, 2, 0, 2 * Math.PI);
+			ctx.fill();
+		}
+	}
+
+	if(hotspotClusters !== null && hotspotClusters.length > 0) {
+		let maxCount = hotspotClusters[0].Count;
+		ctx.strokeStyle = "black";
+		ctx.lineWidth = 2;
+		ctx.font = "bold 16px sans-serif";
+		ctx.textAlign = "center";
+		ctx.textBaseline = "middle";
+		for(let i = 0; i < hotspotClusters.length; i++) {
+			let x = hotspotClusters[i].X * w;
+			let y = hotspotClusters[i].Y * h;
+			ctx.beginPath();
+			ctx.arc(x, y, r * (0.5 + Math.sqrt(hotspotClusters[i].Count / maxCount)), 0, 2 * Math.PI);
+			ctx.stroke();
+			ctx.fillStyle = "black";
+			ctx.fillText(String(i + 1), x, y);
+		}
+	}
+}
+
+{{if not .Finished}}
+data_function = function(b) {
+	try {
+		let data = JSON.parse(b);
+		hotspotPoints = hotspotPoints.concat(data);
+		document.getElementById("hotspotSubmitted").textContent = hotspotPoints.length;
+		hotspotDraw();
+	} catch (e) {
+		console.log(e);
+	}
+};
+{{end}}
+
+document.getElementById("hotspotImage").onload = hotspotDraw;
+hotspotDraw();
+</script>
+`
+
+var hotspotAdminTemplate = template.Must(template.New("hotspotAdmin").Funcs(template.FuncMap{"percent": func(count int, p []hotspotPoint) string {
+	if len(p) == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%s%%", helper.FormatFloat(float64(count)/float64(len(p))*100))
+}}).Parse(hotspotAdmin))
+
+type hotspotAdminStruct struct {
+	Question    string
+	Image       string
+	Points      []hotspotPoint
+	Clusters    []hotspotCluster
+	Finished    bool
+	Translation translation.Translation
+}
+
+type hotspotGetConfig struct {
+	Question string
+	Image    string
+	Points   int
+}
+
+type hotspotPoint struct {
+	X float64
+	Y float64
+}
+
+type hotspotCluster struct {
+	X     float64
+	Y     float64
+	Count int
+}
+
+type hotspot struct {
+	adminHTML        chan<- template.HTML
+	userHTML         chan<- template.HTML
+	adminInput       <-chan []byte
+	userInput        <-chan []byte
+	participantInput <-chan registry.ParticipantInput
+	adminData        chan<- []byte
+	userData         chan<- []byte
+	ctx              context.Context
+	cancel           context.CancelFunc
+
+	question string
+	image    string
+	points   []hotspotPoint
+	limit    int            // number of points per participant
+	used     map[string]int // number of points per participant
+	sent     int            // number of points already send to admins
+	clusters []hotspotCluster
+	finished bool
+	l        sync.Mutex
+}
+
+func (h *hotspot) ConfigHTML() (string, template.HTML) {
+	tl := translation.GetDefaultTranslation()
+	td := hotspotConfigStruct{
+		DefaultPoints: hotspotDefaultPoints,
+		MaxPoints:     hotspotMaxPoints,
+		Translation:   tl,
+	}
+	var buf bytes.Buffer
+	err := hotspotConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing hotspot config: %s", err.Error())
+	}
+
+	return tl.DisplayHotspot, template.HTML(buf.Bytes())
+}
+
+func (h *hotspot) AdminHTMLChannel(c chan<- template.HTML) {
+	h.adminHTML = c
+}
+
+func (h *hotspot) UserHTMLChannel(c chan<- template.HTML) {
+	h.userHTML = c
+}
+
+func (h *hotspot) ReceiveUserChannel(c <-chan []byte) {
+	h.userInput = c
+}
+
+func (h *hotspot) ReceiveUserParticipantChannel(c <-chan registry.ParticipantInput) {
+	h.participantInput = c
+}
+
+func (h *hotspot) ReceiveAdminChannel(c <-chan []byte) {
+	h.adminInput = c
+}
+
+func (h *hotspot) AdminDataChannel(c chan<- []byte) {
+	h.adminData = c
+}
+
+func (h *hotspot) UserDataChannel(c chan<- []byte) {
+	h.userData = c
+}
+
+func (h *hotspot) Activate(b []byte) error {
+	var config hotspotGetConfig
+	err := json.Unmarshal(b, &config)
+	if err != nil {
+		return err
+	}
+
+	if config.Question == "" {
+		return errors.New("hotspot: no question found")
+	}
+	if !helper.IsInternalImageURL(config.Image) {
+		return fmt.Errorf("hotspot: '%s' is not an uploaded image", config.Image)
+	}
+	if config.Points == 0 {
+		config.Points = hotspotDefaultPoints
+	}
+	if config.Points < 0 || config.Points > hotspotMaxPoints {
+		return fmt.Errorf("hotspot: points per participant must be between 1 and %d", hotspotMaxPoints)
+	}
+	h.question = config.Question
+	h.image = config.Image
+	h.limit = config.Points
+	h.used = make(map[string]int)
+
+	go func() {
+		h.userHTML <- h.GetLastHTMLUser()
+	}()
+	go func() {
+		h.adminHTML <- h.GetLastHTMLAdmin()
+	}()
+
+	h.ctx = context.Background()
+	h.ctx, h.cancel = context.WithCancel(h.ctx)
+	go h.worker(h.ctx)
+	return nil
+}
+
+func (h *hotspot) worker(ctx context.Context) {
+	done := ctx.Done()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case b := <-h.adminInput:
+			if string(b) != "close" {
+				continue
+			}
+			h.l.Lock()
+			if h.finished {
+				h.l.Unlock()
+				continue
+			}
+			h.finished = true
+			h.sent = len(h.points)
+			h.clusters = clusterHotspotPoints(h.points, hotspotClusterRadius)
+			h.l.Unlock()
+			h.adminHTML <- h.GetLastHTMLAdmin()
+			h.userHTML <- h.GetLastHTMLUser()
+
+		case b := <-h.userInput:
+			h.receive(registry.ParticipantInput{Data: b})
+
+		case p := <-h.participantInput:
+			h.receive(p)
+
+		case <-ticker.C:
+			h.l.Lock()
+			if h.finished || h.sent == len(h.points) {
+				h.l.Unlock()
+				continue
+			}
+			b, err := json.Marshal(h.points[h.sent:])
+			h.sent = len(h.points)
+			h.l.Unlock()
+			if err != nil {
+				log.Printf("hotspot: Error marshaling update: (%s)", err.Error())
+				continue
+			}
+			h.adminData <- b
+
+		case <-done:
+			return
+		}
+	}
+}
+
+// receive adds a point of a participant unless the participant has already placed all points.
+// Inputs without a participant share a single limit.
+func (h *hotspot) receive(input registry.ParticipantInput) {
+	var p hotspotPoint
+	err := json.Unmarshal(input.Data, &p)
+	if err != nil {
+		return
+	}
+	if math.IsNaN(p.X) || math.IsNaN(p.Y) || p.X < 0 || p.X > 1 || p.Y < 0 || p.Y > 1 {
+		return
+	}
+	h.l.Lock()
+	defer h.l.Unlock()
+	if h.finished || h.used[input.Participant] >= h.limit {
+		return
+	}
+	h.used[input.Participant]++
+	h.points = append(h.points, p)
+}
+
+func (h *hotspot) GetLastHTMLUser() template.HTML {
+	h.l.Lock()
+	finished := h.finished
+	h.l.Unlock()
+
+	if finished {
+		return h.getAdminPage()
+	}
+
+	td := hotspotUserStruct{
+		Question:    h.question,
+		Image:       h.image,
+		Points:      h.limit,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := hotspotUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing hotspot user: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (h *hotspot) IsClosed() bool {
+	h.l.Lock()
+	defer h.l.Unlock()
+	return h.finished
+}
+
+func (h *hotspot) GetLastHTMLAdmin() template.HTML {
+	return h.getAdminPage()
+}
+
+func (h *hotspot) Deactivate() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+}
+
+func (h *hotspot) getAdminPage() template.HTML {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	// Only include points already send to the admins - all others will be transmitted through the data channel.
+	points := make([]hotspotPoint, h.sent)
+	copy(points, h.points)
+
+	td := hotspotAdminStruct{
+		Question:    h.question,
+		Image:       h.image,
+		Points:      points,
+		Clusters:    h.clusters,
+		Finished:    h.finished,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := hotspotAdminTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing hotspot admin: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+type hotspotResultStruct struct {
+	Question string
+	Image    string
+	Points   []hotspotPoint
+	Clusters []hotspotCluster
+}
+
+func (h *hotspot) GetAdminDownload() []byte {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	clusters := h.clusters
+	if !h.finished {
+		clusters = clusterHotspotPoints(h.points, hotspotClusterRadius)
+	}
+
+	r := hotspotResultStruct{
+		Question: h.question,
+		Image:    h.image,
+		Points:   h.points,
+		Clusters: clusters,
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return []byte(err.Error())
+	}
+	return b
+}
+
+// clusterHotspotPoints groups nearby points.
+// Each point is assigned to the nearest existing cluster within radius or starts a new cluster.
+// Afterwards, the assignment is refined a few times by moving each point to the nearest centre.
+// Since centres move during refinement, a point might end up slightly farther than radius from the centre of its cluster.
+// The result is sorted by the number of points, largest cluster first.
+func clusterHotspotPoints(points []hotspotPoint, radius float64) []hotspotCluster {
+	if len(points) == 0 {
+		return nil
+	}
+
+	distance := func(p hotspotPoint, c hotspotCluster) float64 {
+		return math.Hypot(p.X-c.X, p.Y-c.Y)
+	}
+
+	clusters := make([]hotspotCluster, 0)
+	for i := range points {
+		best := -1
+		for j := range clusters {
+			if d := distance(points[i], clusters[j]); d <= radius && (best == -1 || d < distance(points[i], clusters[best])) {
+				best = j
+			}
+		}
+		if best == -1 {
+			clusters = append(clusters, hotspotCluster{X: points[i].X, Y: points[i].Y, Count: 1})
+			continue
+		}
+		c := &clusters[best]
+		c.X = (c.X*float64(c.Count) + points[i].X) / float64(c.Count+1)
+		c.Y = (c.Y*float64(c.Count) + points[i].Y) / float64(c.Count+1)
+		c.Count++
+	}
+
+	for iteration := 0; iteration < 5; iteration++ {
+		sumX := make([]float64, len(clusters))
+		sumY := make([]float64, len(clusters))
+		count := make([]int, len(clusters))
+		for i := range points {
+			best := 0
+			for j := range clusters {
+				if distance(points[i], clusters[j]) < distance(points[i], clusters[best]) {
+					best = j
+				}
+			}
+			sumX[best] += points[i].X
+			sumY[best] += points[i].Y
+			count[best]++
+		}
+		next := make([]hotspotCluster, 0, len(clusters))
+		for j := range clusters {
+			if count[j] == 0 {
+				continue
+			}
+			next = append(next, hotspotCluster{X: sumX[j] / float64(count[j]), Y: sumY[j] / float64(count[j]), Count: count[j]})
+		}
+		clusters = next
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].Count > clusters[j].Count })
+	return clusters
+}
@@ -0,0 +1,433 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/responsego/helper"
+	"github.com/Top-Ranger/responsego/registry"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+func init() {
+	err := registry.RegisterFeedbackPlugin(func() registry.FeedbackPlugin { return new(imageChoice) }, "ImageChoice")
+	if err != nil {
+		panic(err)
+	}
+}
+
+const imageChoiceConfig = `
+<h1>{{.Translation.DisplayImageChoice}}</h1>
+<p>{{.Translation.DisplayQuestion}}: <input id="ImageChoiceQuestion" type="text"></p>
+<p><input id="ImageChoiceFile" type="file" accept="image/png,image/jpeg,image/gif,image/webp" multiple> <button onclick="imageChoiceUpload()">{{.Translation.Upload}}</button></p>
+<ol id="ImageChoiceList"></ol>
+<p><button onclick="sendActivate('ImageChoice', imageChoiceGetData())">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('ImageChoice', imageChoiceGetData(), '{{.Translation.DisplayImageChoice}}: '+document.getElementById('ImageChoiceQuestion').value)">{{.Translation.SaveElement}}</button></p>
+
+<script>
+var imageChoiceImages = [];
+
+function imageChoiceRender() {
+	let list = document.getElementById('ImageChoiceList');
+	list.innerHTML = "";
+	for(let i = 0; i < imageChoiceImages.length; i++) {
+		let li = document.createElement("LI");
+		let img = document.createElement("IMG");
+		img.src = imageChoiceImages[i].URL;
+		img.classList.add("icon");
+		li.appendChild(img);
+		let caption = document.createElement("INPUT");
+		caption.type = "text";
+		caption.placeholder = "{{.Translation.Caption}}";
+		caption.value = imageChoiceImages[i].Caption;
+		caption.onchange = function() {
+			imageChoiceImages[i].Caption = caption.value;
+		};
+		li.appendChild(caption);
+		let remove = document.createElement("BUTTON");
+		remove.textContent = "{{.Translation.Remove}}";
+		remove.onclick = function() {
+			imageChoiceImages.splice(i, 1);
+			imageChoiceRender();
+		};
+		li.appendChild(remove);
+		list.appendChild(li);
+	}
+}
+
+function imageChoiceUpload() {
+	let files = document.getElementById('ImageChoiceFile').files;
+	for(let i = 0; i < files.length; i++) {
+		uploadImage(files[i], function(url) {
+			if(url !== null) {
+				imageChoiceImages.push({"URL": url, "Caption": ""});
+				imageChoiceRender();
+			}
+		});
+	}
+	document.getElementById('ImageChoiceFile').value = "";
+}
+
+function imageChoiceGetData() {
+	let data = {"Question": document.getElementById('ImageChoiceQuestion').value, "Images": imageChoiceImages};
+	return JSON.stringify(data);
+}
+</script>
+`
+
+var imageChoiceConfigTemplate = template.Must(template.New("imageChoiceConfig").Parse(imageChoiceConfig))
+
+type imageChoiceConfigStruct struct {
+	Translation translation.Translation
+}
+
+const imageChoiceUser = `
+<h1>{{.Question}}</h1>
+<div class="imagechoice">
+{{range $i, $e := .Images}}
+	<div class="imagechoiceitem" id="ImageChoice_item_{{$i}}">
+		<img src="{{$e.URL}}" alt="{{addOne $i}}">
+		{{$e.Caption}}
+		<p><button class="ImageChoiceButton" onclick="sendData('ImageChoice','{{$i}}');document.getElementById('ImageChoice_item_{{$i}}').style.backgroundColor='var(--primary-colour-dark)';document.querySelectorAll('.ImageChoiceButton').forEach(function(e){e.disabled=true;});">{{$.Translation.Submit}}</button></p>
+	</div>
+{{end}}
+</div>
+`
+
+var imageChoiceUserTemplate = template.Must(template.New("imageChoiceUser").Funcs(template.FuncMap{"addOne": func(i int) int { return i + 1 }}).Parse(imageChoiceUser))
+
+type imageChoiceUserStruct struct {
+	Question    string
+	Images      []imageChoiceImage
+	Translation translation.Translation
+}
+
+const imageChoiceAdmin = `
+{{if .Chart}}
+{{.Chart}}
+{{else}}
+<h1>{{.Question}}</h1>
+{{end}}
+<div class="imagechoice">
+{{range $i, $e := .Images}}
+	<div class="imagechoiceitem">
+		<h2>{{addOne $i}}</h2>
+		<img src="{{$e.URL}}" alt="{{addOne $i}}">
+		{{$e.Caption}}
+		{{if $.ShowCount}}<p><strong>{{index $.Count $i}}</strong></p>{{end}}
+	</div>
+{{end}}
+</div>
+{{if not .Chart}}
+<p><em>{{.Translation.Submitted}}: {{.Submitted}}</em></p>
+<p><button onclick="sendData('ImageChoice', 'close')">{{.Translation.Finish}}</button></p>
+{{end}}
+`
+
+var imageChoiceAdminTemplate = template.Must(template.New("imageChoiceAdmin").Funcs(template.FuncMap{"addOne": func(i int) int { return i + 1 }}).Parse(imageChoiceAdmin))
+
+type imageChoiceAdminStruct struct {
+	Question    string
+	Chart       template.HTML
+	Images      []imageChoiceImage
+	ShowCount   bool
+	Count       []int
+	Submitted   int
+	Translation translation.Translation
+}
+
+type imageChoiceImage struct {
+	URL     string
+	Caption template.HTML
+}
+
+type imageChoiceGetConfig struct {
+	Question string
+	Images   []struct {
+		URL     string
+		Caption string
+	}
+}
+
+type imageChoice struct {
+	adminHTML  chan<- template.HTML
+	userHTML   chan<- template.HTML
+	adminInput <-chan []byte
+	userInput  <-chan []byte
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	question        string
+	images          []imageChoiceImage
+	captions        []string
+	answerCount     []int
+	numberSubmitted int
+	changed         bool
+	finished        bool
+	l               sync.Mutex
+}
+
+func (ic *imageChoice) ConfigHTML() (string, template.HTML) {
+	tl := translation.GetDefaultTranslation()
+	td := imageChoiceConfigStruct{
+		Translation: tl,
+	}
+	var buf bytes.Buffer
+	err := imageChoiceConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing imageChoice config: %s", err.Error())
+	}
+
+	return tl.DisplayImageChoice, template.HTML(buf.Bytes())
+}
+
+func (ic *imageChoice) AdminHTMLChannel(c chan<- template.HTML) {
+	ic.adminHTML = c
+}
+
+func (ic *imageChoice) UserHTMLChannel(c chan<- template.HTML) {
+	ic.userHTML = c
+}
+
+func (ic *imageChoice) ReceiveUserChannel(c <-chan []byte) {
+	ic.userInput = c
+}
+
+func (ic *imageChoice) ReceiveAdminChannel(c <-chan []byte) {
+	ic.adminInput = c
+}
+
+func (ic *imageChoice) Activate(b []byte) error {
+	var config imageChoiceGetConfig
+	err := json.Unmarshal(b, &config)
+	if err != nil {
+		return err
+	}
+
+	if config.Question == "" {
+		return errors.New("imagechoice: no question found")
+	}
+	ic.question = config.Question
+
+	for i := range config.Images {
+		if !helper.IsInternalImageURL(config.Images[i].URL) {
+			return fmt.Errorf("imagechoice: '%s' is not an uploaded image", config.Images[i].URL)
+		}
+		ic.images = append(ic.images, imageChoiceImage{URL: config.Images[i].URL, Caption: helper.FormatWithInternalImages([]byte(config.Images[i].Caption))})
+		ic.captions = append(ic.captions, strings.TrimSpace(config.Images[i].Caption))
+	}
+
+	if len(ic.images) == 0 {
+		return errors.New("imagechoice: no images found")
+	}
+
+	ic.answerCount = make([]int, len(ic.images))
+
+	go func() {
+		ic.userHTML <- ic.GetLastHTMLUser()
+	}()
+	go func() {
+		ic.adminHTML <- ic.GetLastHTMLAdmin()
+	}()
+
+	ic.ctx = context.Background()
+	ic.ctx, ic.cancel = context.WithCancel(ic.ctx)
+	go func() {
+		done := ic.ctx.Done()
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case b := <-ic.adminInput:
+				ic.l.Lock()
+				if string(b) == "close" && !ic.finished {
+					ic.finished = true
+					ic.l.Unlock()
+					ic.adminHTML <- ic.GetLastHTMLAdmin()
+					ic.userHTML <- ic.GetLastHTMLUser()
+				} else {
+					ic.l.Unlock()
+				}
+
+			case b := <-ic.userInput:
+				i, err := strconv.Atoi(string(b))
+				if err == nil {
+					ic.l.Lock()
+					if !ic.finished && i >= 0 && i < len(ic.answerCount) {
+						ic.answerCount[i]++
+						ic.numberSubmitted++
+						ic.changed = true
+					}
+					ic.l.Unlock()
+				}
+
+			case <-ticker.C:
+				ic.l.Lock()
+				finished := ic.finished
+				changed := ic.changed
+				ic.changed = false
+				ic.l.Unlock()
+
+				if !finished && changed {
+					ic.adminHTML <- ic.GetLastHTMLAdmin()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (ic *imageChoice) GetLastHTMLUser() template.HTML {
+	ic.l.Lock()
+	finished := ic.finished
+	ic.l.Unlock()
+
+	if finished {
+		return ic.getResultPage()
+	}
+
+	td := imageChoiceUserStruct{
+		Question:    ic.question,
+		Images:      ic.images,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := imageChoiceUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing imageChoice user: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (ic *imageChoice) IsClosed() bool {
+	ic.l.Lock()
+	defer ic.l.Unlock()
+	return ic.finished
+}
+
+func (ic *imageChoice) GetLastHTMLAdmin() template.HTML {
+	ic.l.Lock()
+	finished := ic.finished
+	ic.l.Unlock()
+
+	if finished {
+		return ic.getResultPage()
+	}
+
+	ic.l.Lock()
+	defer ic.l.Unlock()
+	td := imageChoiceAdminStruct{
+		Question:    ic.question,
+		Images:      ic.images,
+		ShowCount:   true,
+		Count:       ic.answerCount,
+		Submitted:   ic.numberSubmitted,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := imageChoiceAdminTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing imageChoice admin: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (ic *imageChoice) Deactivate() {
+	if ic.cancel != nil {
+		ic.cancel()
+	}
+}
+
+// label returns the chart label of the image with index i.
+func (ic *imageChoice) label(i int) string {
+	if ic.captions[i] == "" {
+		return strconv.Itoa(i + 1)
+	}
+	c := []rune(ic.captions[i])
+	if len(c) > 40 {
+		c = append(c[:40], '…')
+	}
+	return fmt.Sprintf("%d: %s", i+1, string(c))
+}
+
+func (ic *imageChoice) getResultPage() template.HTML {
+	ic.l.Lock()
+	defer ic.l.Unlock()
+
+	v := make([]helper.ChartValue, len(ic.answerCount))
+	for i := range ic.answerCount {
+		v[i].Label = ic.label(i)
+		v[i].Value = float64(ic.answerCount[i])
+	}
+
+	td := imageChoiceAdminStruct{
+		Question:    ic.question,
+		Chart:       helper.BarChart(v, "ImageChoice_chart", ic.question),
+		Images:      ic.images,
+		Count:       ic.answerCount,
+		Submitted:   ic.numberSubmitted,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := imageChoiceAdminTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing imageChoice result: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+type imageChoiceResultStruct struct {
+	Question    string
+	Images      []string
+	Captions    []string
+	AnswerCount []int
+	Submitted   int
+}
+
+func (ic *imageChoice) GetAdminDownload() []byte {
+	ic.l.Lock()
+	defer ic.l.Unlock()
+
+	r := imageChoiceResultStruct{
+		Question:    ic.question,
+		Images:      make([]string, len(ic.images)),
+		Captions:    ic.captions,
+		AnswerCount: ic.answerCount,
+		Submitted:   ic.numberSubmitted,
+	}
+	for i := range ic.images {
+		r.Images[i] = ic.images[i].URL
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return []byte(err.Error())
+	}
+	return b
+}
@@ -0,0 +1,522 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/responsego/helper"
+	"github.com/Top-Ranger/responsego/registry"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+func init() {
+	err := registry.RegisterFeedbackPlugin(func() registry.FeedbackPlugin { return new(matrix) }, "Matrix")
+	if err != nil {
+		panic(err)
+	}
+}
+
+const matrixConfig = `
+<h1>{{.Translation.DisplayMatrix}}</h1>
+<p>{{.Translation.DisplayQuestion}}: <input id="MatrixQuestion" type="text"></p>
+<h2>{{.Translation.MatrixRows}}</h2>
+<ol id="MatrixRows"></ol>
+<p><button onclick="matrixAddRow('', false)">{{.Translation.Add}}</button></p>
+<h2>{{.Translation.MatrixColumns}}</h2>
+<textarea id="MatrixColumns" rows="4"></textarea>
+<p><button onclick="sendActivate('Matrix', matrixGetData())">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('Matrix', matrixGetData(), '{{.Translation.DisplayMatrix}}: '+document.getElementById('MatrixQuestion').value)">{{.Translation.SaveElement}}</button></p>
+
+<script>
+function matrixAddRow(text, multi) {
+	let li = document.createElement("LI");
+	let input = document.createElement("INPUT");
+	input.type = "text";
+	input.value = text;
+	input.classList.add("MatrixRowText");
+	li.appendChild(input);
+	let checkbox = document.createElement("INPUT");
+	checkbox.type = "checkbox";
+	checkbox.checked = multi;
+	checkbox.classList.add("MatrixRowMulti");
+	li.appendChild(checkbox);
+	let label = document.createElement("SPAN");
+	label.textContent = " {{.Translation.MultipleAnswers}} ";
+	li.appendChild(label);
+	let remove = document.createElement("BUTTON");
+	remove.textContent = "{{.Translation.Remove}}";
+	remove.onclick = function() {
+		li.remove();
+	};
+	li.appendChild(remove);
+	document.getElementById("MatrixRows").appendChild(li);
+}
+
+function matrixGetData() {
+	let rows = [];
+	document.querySelectorAll("#MatrixRows li").forEach(function(li) {
+		let text = li.querySelector(".MatrixRowText").value;
+		if(text.trim() !== "") {
+			rows.push({"Text": text, "Multi": li.querySelector(".MatrixRowMulti").checked});
+		}
+	});
+	let columns = document.getElementById('MatrixColumns').value.split("\n").map(function(c) { return c.trim(); }).filter(function(c) { return c !== ""; });
+	let data = {"Question": document.getElementById('MatrixQuestion').value, "Rows": rows, "Columns": columns};
+	return JSON.stringify(data);
+}
+
+matrixAddRow('', false);
+</script>
+`
+
+var matrixConfigTemplate = template.Must(template.New("matrixConfig").Parse(matrixConfig))
+
+type matrixConfigStruct struct {
+	Translation translation.Translation
+}
+
+const matrixUser = `
+<h1>{{.Question}}</h1>
+{{if .Result}}
+{{.Result}}
+{{else}}
+<table>
+<tr><th></th>{{range $j, $c := .Columns}}<th>{{$c}}</th>{{end}}</tr>
+{{range $i, $r := .Rows}}
+<tr>
+<td>{{$r.Text}}</td>
+{{range $j, $c := $.Columns}}
+<td class="centre"><input class="MatrixInput" type="{{if $r.Multi}}checkbox{{else}}radio{{end}}" name="Matrix_{{$i}}" data-row="{{$i}}" value="{{$j}}" aria-label="{{$c}}"></td>
+{{end}}
+</tr>
+{{end}}
+</table>
+<p><button id="MatrixSubmit" onclick="matrixSubmit()">{{.Translation.Submit}}</button></p>
+
+<script>
+function matrixSubmit() {
+	let answers = [];
+	for(let i = 0; i < {{len .Rows}}; i++) {
+		answers.push([]);
+	}
+	document.querySelectorAll(".MatrixInput").forEach(function(e) {
+		if(e.checked) {
+			answers[parseInt(e.dataset.row)].push(parseInt(e.value));
+		}
+		e.disabled = true;
+	});
+	sendData('Matrix', JSON.stringify({"Answers": answers}));
+	document.getElementById("MatrixSubmit").disabled = true;
+}
+</script>
+{{end}}
+`
+
+var matrixUserTemplate = template.Must(template.New("matrixUser").Parse(matrixUser))
+
+type matrixUserStruct struct {
+	Question    string
+	Rows        []matrixRow
+	Columns     []string
+	Result      template.HTML
+	Translation translation.Translation
+}
+
+const matrixResult = `
+<table>
+<tr><th></th>{{range $j, $c := .Columns}}<th>{{$c}}</th>{{end}}<th>{{.Translation.Submitted}}</th></tr>
+{{range $i, $r := .Rows}}
+<tr>
+<td>{{$r.Text}}</td>
+{{range $j, $c := $r.Cells}}
+<td class="centre" style="{{$c.Style}}">{{$c.Count}} ({{$c.Percent}}%)</td>
+{{end}}
+<td class="centre">{{$r.Respondents}}</td>
+</tr>
+{{end}}
+</table>
+{{range $i, $c := .Charts}}
+{{$c}}
+{{end}}
+`
+
+var matrixResultTemplate = template.Must(template.New("matrixResult").Parse(matrixResult))
+
+type matrixResultCell struct {
+	Count   int
+	Percent string
+	Style   template.CSS
+}
+
+type matrixResultRow struct {
+	Text        string
+	Cells       []matrixResultCell
+	Respondents int
+}
+
+type matrixResultStruct struct {
+	Columns     []string
+	Rows        []matrixResultRow
+	Charts      []template.HTML
+	Translation translation.Translation
+}
+
+const matrixAdmin = `
+<h1>{{.Question}}</h1>
+{{.Result}}
+{{if not .Finished}}
+<p><em>{{.Translation.Submitted}}: {{.Submitted}}</em></p>
+<p><button onclick="sendData('Matrix', 'close')">{{.Translation.Finish}}</button></p>
+{{end}}
+`
+
+var matrixAdminTemplate = template.Must(template.New("matrixAdmin").Parse(matrixAdmin))
+
+type matrixAdminStruct struct {
+	Question    string
+	Result      template.HTML
+	Submitted   int
+	Finished    bool
+	Translation translation.Translation
+}
+
+type matrixRow struct {
+	Text  string
+	Multi bool
+}
+
+type matrixGetConfig struct {
+	Question string
+	Rows     []matrixRow
+	Columns  []string
+}
+
+type matrixAnswer struct {
+	Answers [][]int
+}
+
+type matrix struct {
+	adminHTML        chan<- template.HTML
+	userHTML         chan<- template.HTML
+	adminInput       <-chan []byte
+	userInput        <-chan []byte
+	participantInput <-chan registry.ParticipantInput
+	ctx              context.Context
+	cancel           context.CancelFunc
+
+	question     string
+	rows         []matrixRow
+	columns      []string
+	counts       [][]int // row -> column -> count
+	respondents  []int   // row -> number of participants answering that row
+	submitted    int
+	participants map[string]bool
+	changed      bool
+	finished     bool
+	l            sync.Mutex
+}
+
+func (m *matrix) ConfigHTML() (string, template.HTML) {
+	tl := translation.GetDefaultTranslation()
+	td := matrixConfigStruct{
+		Translation: tl,
+	}
+	var buf bytes.Buffer
+	err := matrixConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing matrix config: %s", err.Error())
+	}
+
+	return tl.DisplayMatrix, template.HTML(buf.Bytes())
+}
+
+func (m *matrix) AdminHTMLChannel(c chan<- template.HTML) {
+	m.adminHTML = c
+}
+
+func (m *matrix) UserHTMLChannel(c chan<- template.HTML) {
+	m.userHTML = c
+}
+
+func (m *matrix) ReceiveUserChannel(c <-chan []byte) {
+	m.userInput = c
+}
+
+func (m *matrix) ReceiveUserParticipantChannel(c <-chan registry.ParticipantInput) {
+	m.participantInput = c
+}
+
+func (m *matrix) ReceiveAdminChannel(c <-chan []byte) {
+	m.adminInput = c
+}
+
+func (m *matrix) Activate(b []byte) error {
+	var config matrixGetConfig
+	err := json.Unmarshal(b, &config)
+	if err != nil {
+		return err
+	}
+
+	if config.Question == "" {
+		return errors.New("matrix: no question found")
+	}
+	m.question = config.Question
+
+	for i := range config.Rows {
+		if strings.TrimSpace(config.Rows[i].Text) != "" {
+			m.rows = append(m.rows, config.Rows[i])
+		}
+	}
+	for i := range config.Columns {
+		if strings.TrimSpace(config.Columns[i]) != "" {
+			m.columns = append(m.columns, config.Columns[i])
+		}
+	}
+	if len(m.rows) == 0 {
+		return errors.New("matrix: no rows found")
+	}
+	if len(m.columns) == 0 {
+		return errors.New("matrix: no columns found")
+	}
+
+	m.counts = make([][]int, len(m.rows))
+	for i := range m.counts {
+		m.counts[i] = make([]int, len(m.columns))
+	}
+	m.respondents = make([]int, len(m.rows))
+	m.participants = make(map[string]bool)
+
+	go func() {
+		m.userHTML <- m.GetLastHTMLUser()
+	}()
+	go func() {
+		m.adminHTML <- m.GetLastHTMLAdmin()
+	}()
+
+	m.ctx = context.Background()
+	m.ctx, m.cancel = context.WithCancel(m.ctx)
+	go func() {
+		done := m.ctx.Done()
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case b := <-m.adminInput:
+				m.l.Lock()
+				if string(b) == "close" && !m.finished {
+					m.finished = true
+					m.l.Unlock()
+					m.adminHTML <- m.GetLastHTMLAdmin()
+					m.userHTML <- m.GetLastHTMLUser()
+				} else {
+					m.l.Unlock()
+				}
+
+			case b := <-m.userInput:
+				m.receive(registry.ParticipantInput{Data: b})
+
+			case p := <-m.participantInput:
+				m.receive(p)
+
+			case <-ticker.C:
+				m.l.Lock()
+				changed := m.changed && !m.finished
+				m.changed = false
+				m.l.Unlock()
+
+				if changed {
+					m.adminHTML <- m.GetLastHTMLAdmin()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// receive handles an answer of a participant. Only the first valid answer of each participant is counted.
+func (m *matrix) receive(p registry.ParticipantInput) {
+	var a matrixAnswer
+	err := json.Unmarshal(p.Data, &a)
+	if err != nil || len(a.Answers) != len(m.rows) {
+		return
+	}
+
+	// Validate before counting anything
+	for i := range a.Answers {
+		if !m.rows[i].Multi && len(a.Answers[i]) > 1 {
+			return
+		}
+		seen := make(map[int]bool)
+		for _, c := range a.Answers[i] {
+			if c < 0 || c >= len(m.columns) || seen[c] {
+				return
+			}
+			seen[c] = true
+		}
+	}
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	if m.finished || (p.Participant != "" && m.participants[p.Participant]) {
+		return
+	}
+	m.participants[p.Participant] = true
+	m.submitted++
+	for i := range a.Answers {
+		if len(a.Answers[i]) > 0 {
+			m.respondents[i]++
+		}
+		for _, c := range a.Answers[i] {
+			m.counts[i][c]++
+		}
+	}
+	m.changed = true
+}
+
+func (m *matrix) getResult(charts bool) template.HTML {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	td := matrixResultStruct{
+		Columns:     m.columns,
+		Rows:        make([]matrixResultRow, len(m.rows)),
+		Translation: translation.GetDefaultTranslation(),
+	}
+	for i := range m.rows {
+		td.Rows[i] = matrixResultRow{
+			Text:        m.rows[i].Text,
+			Cells:       make([]matrixResultCell, len(m.columns)),
+			Respondents: m.respondents[i],
+		}
+		for j := range m.columns {
+			percent := 0.0
+			if m.respondents[i] > 0 {
+				percent = float64(m.counts[i][j]) / float64(m.respondents[i]) * 100
+			}
+			td.Rows[i].Cells[j] = matrixResultCell{
+				Count:   m.counts[i][j],
+				Percent: helper.FormatFloat(percent),
+				Style:   template.CSS(fmt.Sprintf("background-color: rgba(23, 158, 158, %s)", strconv.FormatFloat(helper.RoundFloat(percent/100*0.8, 2), 'f', -1, 64))),
+			}
+		}
+
+		if charts {
+			v := make([]helper.ChartValue, len(m.columns))
+			for j := range m.columns {
+				v[j] = helper.ChartValue{Label: m.columns[j], Value: float64(m.counts[i][j])}
+			}
+			td.Charts = append(td.Charts, helper.BarChart(v, fmt.Sprintf("Matrix_chart_%d", i), m.rows[i].Text))
+		}
+	}
+
+	var buf bytes.Buffer
+	err := matrixResultTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing matrix result: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (m *matrix) GetLastHTMLUser() template.HTML {
+	m.l.Lock()
+	finished := m.finished
+	m.l.Unlock()
+
+	td := matrixUserStruct{
+		Question:    m.question,
+		Rows:        m.rows,
+		Columns:     m.columns,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	if finished {
+		td.Result = m.getResult(true)
+	}
+
+	var buf bytes.Buffer
+	err := matrixUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing matrix user: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (m *matrix) IsClosed() bool {
+	m.l.Lock()
+	defer m.l.Unlock()
+	return m.finished
+}
+
+func (m *matrix) GetLastHTMLAdmin() template.HTML {
+	m.l.Lock()
+	finished := m.finished
+	submitted := m.submitted
+	m.l.Unlock()
+
+	td := matrixAdminStruct{
+		Question:    m.question,
+		Result:      m.getResult(true),
+		Submitted:   submitted,
+		Finished:    finished,
+		Translation: translation.GetDefaultTranslation(),
+	}
+
+	var buf bytes.Buffer
+	err := matrixAdminTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing matrix admin: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (m *matrix) Deactivate() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
+func (m *matrix) GetAdminDownload() []byte {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"row", "column", "count"})
+	for i := range m.rows {
+		for j := range m.columns {
+			w.Write([]string{m.rows[i].Text, m.columns[j], strconv.Itoa(m.counts[i][j])})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return []byte(err.Error())
+	}
+	return buf.Bytes()
+}
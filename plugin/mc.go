@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020,2023,2025 Marcus Soll
+// Copyright 2020,2023,2025,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -19,7 +19,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"html/template"
 	"log"
 	"strconv"
@@ -40,57 +39,63 @@ func init() {
 }
 
 const mcConfig = `
-<h1>%s</h1>
-<p>%s: <input id="MC" type="text"></p>
-<table style="border: none;">
-    <tr style="border: none; background-color: inherit;">
-        <td style="border: none;"><input id="MC_1" type="text"></td>
-        <td style="border: none;"><input id="MC_2" type="text"></td>
-        <td style="border: none;"><input id="MC_3" type="text"></td>
-    </tr>
-    <tr style="border: none; background-color: inherit;">
-        <td style="border: none;"><input id="MC_4" type="text"></td>
-        <td style="border: none;"><input id="MC_5" type="text"></td>
-        <td style="border: none;"><input id="MC_6" type="text"></td>
-    </tr>
-    <tr style="border: none; background-color: inherit;">
-        <td style="border: none;"><input id="MC_7" type="text"></td>
-        <td style="border: none;"><input id="MC_8" type="text"></td>
-        <td style="border: none;"><input id="MC_9" type="text"></td>
-    </tr>
-</table>
-<p><button onclick="sendActivate('MultipleChoice', JSON.stringify({'1': document.getElementById('MC_1').value, '2': document.getElementById('MC_2').value, '3': document.getElementById('MC_3').value, '4': document.getElementById('MC_4').value, '5': document.getElementById('MC_5').value, '6': document.getElementById('MC_6').value, '7': document.getElementById('MC_7').value, '8': document.getElementById('MC_8').value, '9': document.getElementById('MC_9').value, 'q': document.getElementById('MC').value}))">%s</button></p>
-<p><button onclick="saveElement('MultipleChoice', JSON.stringify({'1': document.getElementById('MC_1').value, '2': document.getElementById('MC_2').value, '3': document.getElementById('MC_3').value, '4': document.getElementById('MC_4').value, '5': document.getElementById('MC_5').value, '6': document.getElementById('MC_6').value, '7': document.getElementById('MC_7').value, '8': document.getElementById('MC_8').value, '9': document.getElementById('MC_9').value, 'q': document.getElementById('MC').value}), '%s: '+document.getElementById('MC').value)">%s</button></p>
+<h1>{{.Translation.DisplayMultipleChoice}}</h1>
+<p>{{.Translation.DisplayMultipleChoice}}: <input id="MC" type="text"></p>
+<h2>{{.Translation.Answers}}</h2>
+<ol id="MC_options"></ol>
+<p><button onclick="choiceAddOption('MC', '', false)">{{.Translation.Add}}</button></p>
+<p>{{.Translation.TimeLimit}}: <input id="MC_tl" type="number" min="0"> {{.Translation.Seconds}}</p>
+<p>{{.Translation.RevealMode}}: <select id="MC_r"><option value="">{{.Translation.RevealAdmin}}</option><option value="live">{{.Translation.RevealLive}}</option><option value="hidden">{{.Translation.RevealHidden}}</option><option value="never">{{.Translation.RevealNever}}</option></select></p>
+<p><button onclick="sendActivate('MultipleChoice', choiceGetData('MC'))">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('MultipleChoice', choiceGetData('MC'), '{{.Translation.DisplayMultipleChoice}}: '+document.getElementById('MC').value)">{{.Translation.SaveElement}}</button></p>
+` + choiceConfigScript + `
+<script>
+choiceAddOption('MC', '', false);
+choiceAddOption('MC', '', false);
+choiceAddOption('MC', '', false);
+</script>
 `
 
+var mcConfigTemplate = template.Must(template.New("mcConfig").Parse(mcConfig))
+
+type mcConfigStruct struct {
+	Translation translation.Translation
+}
+
 const mcUser = `
 <h1>{{.Question}}</h1>
+{{.TimeLimit}}
 <table style="border: none;">
 {{range $i, $e := .Answers}}
     <tr style="border: none;">
         <td id="Question_cell_{{$i}}" style="border: none;"><label for="Question_check_{{$i}}">{{$e}}</label></td>
-		<td style="border: none;"><input type="checkbox" id="Question_check_{{$i}}" name="{{$i}}" value="checked"></td>
+		<td style="border: none;"><input type="checkbox" class="Question_check" id="Question_check_{{$i}}" name="{{$i}}" value="checked"></td>
+		{{if $.Live}}<td id="Question_count_{{$i}}" style="border: none;">{{index $.Counts $i}}</td>{{end}}
 	</tr>
 {{end}}
 </table>
-<button id="Question_button" onclick="sendData('MultipleChoice',''{{range $i, $e := .Answers}}+document.getElementById('Question_check_{{$i}}').checked+';'{{end}});document.getElementById('Question_button').disabled=true;var e=document.getElementById('Question_check_0'); if(e!=null){e.disabled=true; if(e.checked){document.getElementById('Question_cell_0').style.backgroundColor='var(--primary-colour-dark)'};};var e=document.getElementById('Question_check_1'); if(e!=null){e.disabled=true;if(e.checked){document.getElementById('Question_cell_1').style.backgroundColor='var(--primary-colour-dark)'};};var e=document.getElementById('Question_check_2'); if(e!=null){e.disabled=true;if(e.checked){document.getElementById('Question_cell_2').style.backgroundColor='var(--primary-colour-dark)'};};var e=document.getElementById('Question_check_3'); if(e!=null){e.disabled=true;if(e.checked){document.getElementById('Question_cell_3').style.backgroundColor='var(--primary-colour-dark)'};};var e=document.getElementById('Question_check_4'); if(e!=null){e.disabled=true;if(e.checked){document.getElementById('Question_cell_4').style.backgroundColor='var(--primary-colour-dark)'};};var e=document.getElementById('Question_check_5'); if(e!=null){e.disabled=true;if(e.checked){document.getElementById('Question_cell_5').style.backgroundColor='var(--primary-colour-dark)'};};var e=document.getElementById('Question_check_6'); if(e!=null){e.disabled=true;if(e.checked){document.getElementById('Question_cell_6').style.backgroundColor='var(--primary-colour-dark)'};};var e=document.getElementById('Question_check_7'); if(e!=null){e.disabled=true;if(e.checked){document.getElementById('Question_cell_7').style.backgroundColor='var(--primary-colour-dark)'};};var e=document.getElementById('Question_check_8'); if(e!=null){e.disabled=true;if(e.checked){document.getElementById('Question_cell_8').style.backgroundColor='var(--primary-colour-dark)'};};">{{$.Translation.Submit}}</button>
-`
+<button id="Question_button" onclick="sendData('MultipleChoice',''{{range $i, $e := .Answers}}+document.getElementById('Question_check_{{$i}}').checked+';'{{end}});document.getElementById('Question_button').disabled=true;document.querySelectorAll('.Question_check').forEach(function(e){e.disabled=true;if(e.checked){document.getElementById('Question_cell_'+e.name).style.backgroundColor='var(--primary-colour-dark)'};});">{{$.Translation.Submit}}</button>
+` + choiceLiveScript
 
 var mcUserTemplate = template.Must(template.New("mcUser").Parse(mcUser))
 
 type mcUserStruct struct {
 	Question    string
-	Answers     []string
+	Answers     []template.HTML
+	Counts      []int
+	Live        bool
+	TimeLimit   template.HTML
 	Translation translation.Translation
 }
 
 const mcAdmin = `
 <h1>{{.Question}}</h1>
+{{.TimeLimit}}
 <table style="border: none;">
 {{range $i, $e := .Answers}}
     <tr style="border: none;">
         <td style="border: none;">{{$e.Question}}</td>
-		<td style="border: none;">{{$e.Count}}</td>
+		{{if not $.Hidden}}<td style="border: none;">{{$e.Count}}</td>{{end}}
 	</tr>
 {{end}}
 	<tr style="border: none;">
@@ -106,10 +111,12 @@ var mcAdminTemplate = template.Must(template.New("mcAdmin").Parse(mcAdmin))
 type mcAdminStruct struct {
 	Question string
 	Answers  []struct {
-		Question string
+		Question template.HTML
 		Count    int
 	}
 	Submitted   int
+	Hidden      bool
+	TimeLimit   template.HTML
 	Translation translation.Translation
 }
 
@@ -118,21 +125,34 @@ type mc struct {
 	userHTML   chan<- template.HTML
 	adminInput <-chan []byte
 	userInput  <-chan []byte
+	adminData  chan<- []byte
+	userData   chan<- []byte
 	ctx        context.Context
 	cancel     context.CancelFunc
 
 	Question        string
 	QuestionAnswers []string
+	answerHTML      []template.HTML
 	AnswerCount     []int
 	NumberSubmitted int
 	NumberChanged   bool
 	AnswerLock      sync.Mutex
 	Finished        bool
+	limit           timeLimit
+	reveal          revealMode
 }
 
 func (q *mc) ConfigHTML() (string, template.HTML) {
 	tl := translation.GetDefaultTranslation()
-	return tl.DisplayMultipleChoice, template.HTML(fmt.Sprintf(mcConfig, template.HTMLEscapeString(tl.DisplayMultipleChoice), template.HTMLEscapeString(tl.DisplayMultipleChoice), template.HTMLEscapeString(tl.Activate), template.HTMLEscapeString(tl.DisplayMultipleChoice), template.HTMLEscapeString(tl.SaveElement)))
+	td := mcConfigStruct{
+		Translation: tl,
+	}
+	var buf bytes.Buffer
+	err := mcConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing mc config: %s", err.Error())
+	}
+	return tl.DisplayMultipleChoice, template.HTML(buf.Bytes())
 }
 
 func (q *mc) AdminHTMLChannel(c chan<- template.HTML) {
@@ -151,43 +171,34 @@ func (q *mc) ReceiveAdminChannel(c <-chan []byte) {
 	q.adminInput = c
 }
 
+func (q *mc) AdminDataChannel(c chan<- []byte) {
+	q.adminData = c
+}
+
+func (q *mc) UserDataChannel(c chan<- []byte) {
+	q.userData = c
+}
+
 func (q *mc) Activate(b []byte) error {
-	input := make(map[string]string)
-	err := json.Unmarshal(b, &input)
+	c, err := parseChoiceConfig(b)
 	if err != nil {
 		return err
 	}
 
-	q.Question = input["q"]
-	if q.Question == "" {
-		return fmt.Errorf("no question found")
-	}
-
-	for i := 1; i <= 9; i++ {
-		s := input[fmt.Sprintf("%d", i)]
-		if s != "" {
-			q.QuestionAnswers = append(q.QuestionAnswers, s)
-		}
-	}
-
-	if len(q.QuestionAnswers) == 0 {
-		return fmt.Errorf("no answers found")
+	q.Question = c.Question
+	q.QuestionAnswers = make([]string, len(c.Options))
+	q.answerHTML = make([]template.HTML, len(c.Options))
+	for i := range c.Options {
+		q.QuestionAnswers[i] = c.Options[i].Text
+		q.answerHTML[i] = c.Options[i].HTML()
 	}
-
 	q.AnswerCount = make([]int, len(q.QuestionAnswers))
+	q.reveal = c.Reveal
+
+	timeout := q.limit.Start(c.TimeLimit)
 
 	go func() {
-		td := mcUserStruct{
-			Question:    q.Question,
-			Answers:     q.QuestionAnswers,
-			Translation: translation.GetDefaultTranslation(),
-		}
-		var buf bytes.Buffer
-		err := mcUserTemplate.Execute(&buf, td)
-		if err != nil {
-			log.Printf("error executing mcUser: %s", err.Error())
-		}
-		q.userHTML <- template.HTML(buf.Bytes())
+		q.userHTML <- q.getUserPage()
 	}()
 	go func() {
 		q.adminHTML <- q.getAdminPage()
@@ -202,21 +213,17 @@ func (q *mc) Activate(b []byte) error {
 		for {
 			select {
 			case b := <-q.adminInput:
-				q.AnswerLock.Lock()
-				if string(b) == "close" && !q.Finished {
-					q.Finished = true
-					q.AnswerLock.Unlock()
-					t := q.questionGetChart()
-					q.adminHTML <- t
-					q.userHTML <- t
-				} else {
-					q.AnswerLock.Unlock()
+				if string(b) == "close" {
+					q.finish()
 				}
 
+			case <-timeout:
+				q.finish()
+
 			case b := <-q.userInput:
 				split := strings.Split(string(b), ";")
 				q.AnswerLock.Lock()
-				if len(split) >= len(q.AnswerCount) {
+				if !q.Finished && len(split) >= len(q.AnswerCount) {
 					q.NumberSubmitted++
 					q.NumberChanged = true
 					for i := range q.AnswerCount {
@@ -236,6 +243,9 @@ func (q *mc) Activate(b []byte) error {
 
 				if !finished && changed {
 					q.adminHTML <- q.getAdminPage()
+					if q.reveal.userLive() {
+						q.sendCounts()
+					}
 				}
 			case <-done:
 				return
@@ -246,29 +256,24 @@ func (q *mc) Activate(b []byte) error {
 }
 
 func (q *mc) GetLastHTMLUser() template.HTML {
-
-	if q.Finished {
+	if q.IsClosed() {
+		if !q.reveal.userFinal() {
+			return choiceClosedPage(q.Question)
+		}
 		return q.questionGetChart()
 	}
 
+	return q.getUserPage()
+}
+
+func (q *mc) IsClosed() bool {
 	q.AnswerLock.Lock()
 	defer q.AnswerLock.Unlock()
-
-	td := mcUserStruct{
-		Question:    q.Question,
-		Answers:     q.QuestionAnswers,
-		Translation: translation.GetDefaultTranslation(),
-	}
-	var buf bytes.Buffer
-	err := mcUserTemplate.Execute(&buf, td)
-	if err != nil {
-		log.Printf("error executing mcUser: %s", err.Error())
-	}
-	return template.HTML(buf.Bytes())
+	return q.Finished
 }
 
 func (q *mc) GetLastHTMLAdmin() template.HTML {
-	if q.Finished {
+	if q.IsClosed() {
 		return q.questionGetChart()
 	}
 	return q.getAdminPage()
@@ -278,6 +283,59 @@ func (q *mc) Deactivate() {
 	if q.cancel != nil {
 		q.cancel()
 	}
+	q.limit.Stop()
+}
+
+// finish closes the question and sends the results to everyone.
+// It does nothing if the question is already closed.
+func (q *mc) finish() {
+	q.AnswerLock.Lock()
+	if q.Finished {
+		q.AnswerLock.Unlock()
+		return
+	}
+	q.Finished = true
+	q.AnswerLock.Unlock()
+
+	q.limit.Stop()
+	t := q.questionGetChart()
+	q.adminHTML <- t
+	if !q.reveal.userFinal() {
+		t = choiceClosedPage(q.Question)
+	}
+	q.userHTML <- t
+}
+
+// sendCounts sends the current number of answers to the participants.
+func (q *mc) sendCounts() {
+	q.AnswerLock.Lock()
+	b, err := json.Marshal(q.AnswerCount)
+	q.AnswerLock.Unlock()
+	if err != nil {
+		log.Printf("mc: Error marshaling counts: (%s)", err.Error())
+		return
+	}
+	q.userData <- b
+}
+
+func (q *mc) getUserPage() template.HTML {
+	q.AnswerLock.Lock()
+	defer q.AnswerLock.Unlock()
+
+	td := mcUserStruct{
+		Question:    q.Question,
+		Answers:     q.answerHTML,
+		Counts:      q.AnswerCount,
+		Live:        q.reveal.userLive(),
+		TimeLimit:   q.limit.HTML(),
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := mcUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing mcUser: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
 }
 
 func (q *mc) questionGetChart() template.HTML {
@@ -300,17 +358,19 @@ func (q *mc) getAdminPage() template.HTML {
 	td := mcAdminStruct{
 		Question: q.Question,
 		Answers: make([]struct {
-			Question string
+			Question template.HTML
 			Count    int
 		}, 0, len(q.QuestionAnswers)),
 		Submitted:   q.NumberSubmitted,
+		Hidden:      !q.reveal.adminLive(),
+		TimeLimit:   q.limit.HTML(),
 		Translation: translation.GetDefaultTranslation(),
 	}
 	for i := range q.AnswerCount {
 		td.Answers = append(td.Answers, struct {
-			Question string
+			Question template.HTML
 			Count    int
-		}{q.QuestionAnswers[i], q.AnswerCount[i]})
+		}{q.answerHTML[i], q.AnswerCount[i]})
 	}
 
 	var buf bytes.Buffer
@@ -324,7 +384,7 @@ func (q *mc) getAdminPage() template.HTML {
 type mcResultStruct struct {
 	Question        string
 	QuestionAnswers []string
-	AnswerCount     []int
+	AnswerCount     []int `json:",omitempty"`
 	Submitted       int
 }
 
@@ -332,7 +392,12 @@ func (q *mc) GetAdminDownload() []byte {
 	q.AnswerLock.Lock()
 	defer q.AnswerLock.Unlock()
 
-	b, err := json.Marshal(mcResultStruct{Question: q.Question, QuestionAnswers: q.QuestionAnswers, AnswerCount: q.AnswerCount, Submitted: q.NumberSubmitted})
+	r := mcResultStruct{Question: q.Question, QuestionAnswers: q.QuestionAnswers, AnswerCount: q.AnswerCount, Submitted: q.NumberSubmitted}
+	if !q.reveal.adminLive() && !q.Finished {
+		// Results are hidden from the admin until the question is closed
+		r.AnswerCount = nil
+	}
+	b, err := json.Marshal(r)
 	if err != nil {
 		return []byte(err.Error())
 	}
@@ -0,0 +1,574 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/responsego/helper"
+	"github.com/Top-Ranger/responsego/registry"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+func init() {
+	err := registry.RegisterFeedbackPlugin(func() registry.FeedbackPlugin { return new(nps) }, "NPS")
+	if err != nil {
+		panic(err)
+	}
+}
+
+const (
+	npsMaxScore        = 10
+	npsMinPromoter     = 9
+	npsMinPassive      = 7
+	npsMaxReasonLength = 1000
+)
+
+const npsConfig = `
+<h1>{{.Translation.DisplayNPS}}</h1>
+<p>{{.Translation.DisplayQuestion}}: <input id="NPSQuestion" type="text" class="fullwidth" value="{{.Translation.NPSDefaultQuestion}}"></p>
+<p><input id="NPSReason" type="checkbox" checked> <label for="NPSReason">{{.Translation.AskForReason}}</label></p>
+<p><label for="NPSPrevious">{{.Translation.CompareWithPrevious}}:</label> <input id="NPSPrevious" type="file" accept=".data,.json,application/json,text/plain" onchange="npsLoadPrevious()"> <span id="NPSPreviousStatus"></span></p>
+<p><button onclick="sendActivate('NPS', npsGetData())">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('NPS', npsGetData(), '{{.Translation.DisplayNPS}}: '+document.getElementById('NPSQuestion').value)">{{.Translation.SaveElement}}</button></p>
+
+<script>
+var npsPrevious = null;
+
+function npsLoadPrevious() {
+	let files = document.getElementById('NPSPrevious').files;
+	let status = document.getElementById('NPSPreviousStatus');
+	if(files.length === 0) {
+		npsPrevious = null;
+		status.textContent = "";
+		return;
+	}
+	let reader = new FileReader();
+	reader.onload = function() {
+		try {
+			npsPrevious = JSON.parse(reader.result);
+			status.textContent = "NPS: " + npsPrevious.Score;
+		} catch (e) {
+			npsPrevious = null;
+			status.textContent = e;
+		}
+	};
+	reader.readAsText(files[0]);
+}
+
+function npsGetData() {
+	let data = {"Question": document.getElementById('NPSQuestion').value, "Reason": document.getElementById('NPSReason').checked, "Previous": npsPrevious};
+	return JSON.stringify(data);
+}
+</script>
+`
+
+var npsConfigTemplate = template.Must(template.New("npsConfig").Parse(npsConfig))
+
+type npsConfigStruct struct {
+	Translation translation.Translation
+}
+
+const npsUser = `
+<h1>{{.Question}}</h1>
+{{if .Result}}
+{{.Result}}
+{{else}}
+<div class="npsscale">
+{{range $i, $e := .Scale}}
+<button id="NPS_button_{{$e}}" class="NPSButton" onclick="npsSelect({{$e}})">{{$e}}</button>
+{{end}}
+</div>
+<div class="npsscale"><span>{{.Translation.NotLikely}}</span><span>{{.Translation.ExtremelyLikely}}</span></div>
+{{if .Reason}}
+<p><label for="NPSReasonText">{{.Translation.Reason}}:</label></p>
+<textarea id="NPSReasonText" maxlength="{{.MaxReason}}"></textarea>
+{{end}}
+<p><button id="NPSSubmit" onclick="npsSubmit()" disabled>{{.Translation.Submit}}</button></p>
+
+<script>
+var npsSelected = -1;
+
+function npsSelect(i) {
+	npsSelected = i;
+	document.querySelectorAll('.NPSButton').forEach(function(e) {
+		e.style.backgroundColor = '';
+	});
+	document.getElementById('NPS_button_' + i).style.backgroundColor = 'var(--primary-colour-dark)';
+	document.getElementById('NPSSubmit').disabled = false;
+}
+
+function npsSubmit() {
+	if(npsSelected < 0) {
+		return;
+	}
+	let reason = document.getElementById('NPSReasonText');
+	sendData('NPS', JSON.stringify({"Score": npsSelected, "Reason": reason === null ? "" : reason.value}));
+	document.querySelectorAll('.NPSButton').forEach(function(e) {
+		e.disabled = true;
+	});
+	document.getElementById('NPSSubmit').disabled = true;
+	if(reason !== null) {
+		reason.disabled = true;
+	}
+}
+</script>
+{{end}}
+`
+
+var npsUserTemplate = template.Must(template.New("npsUser").Parse(npsUser))
+
+type npsUserStruct struct {
+	Question    string
+	Scale       []int
+	Reason      bool
+	MaxReason   int
+	Result      template.HTML
+	Translation translation.Translation
+}
+
+const npsResult = `
+<p class="countdown">{{.Current.Score}}</p>
+{{if .Previous}}
+<p class="centre"><strong>{{.Translation.Previous}}: {{.Previous.Score}} ({{.Delta}})</strong></p>
+{{end}}
+<table>
+<tr><th></th><th>{{.Translation.Detractors}} (0–6)</th><th>{{.Translation.Passives}} (7–8)</th><th>{{.Translation.Promoters}} (9–10)</th><th>{{.Translation.Submitted}}</th></tr>
+<tr><td>{{.Translation.Current}}</td><td>{{.Current.Detractors}}</td><td>{{.Current.Passives}}</td><td>{{.Current.Promoters}}</td><td>{{.Current.Total}}</td></tr>
+{{if .Previous}}
+<tr><td>{{.Translation.Previous}}</td><td>{{.Previous.Detractors}}</td><td>{{.Previous.Passives}}</td><td>{{.Previous.Promoters}}</td><td>{{.Previous.Total}}</td></tr>
+{{end}}
+</table>
+{{.Chart}}
+{{if .Admin}}
+{{.Histogram}}
+{{range $i, $e := .Reasons}}
+{{if $e.Reasons}}
+<h2>{{$e.Label}}</h2>
+<ul>
+{{range $j, $r := $e.Reasons}}
+<li>{{$r.Score}}: {{$r.Reason}}</li>
+{{end}}
+</ul>
+{{end}}
+{{end}}
+{{end}}
+`
+
+var npsResultTemplate = template.Must(template.New("npsResult").Parse(npsResult))
+
+type npsResultTemplateStruct struct {
+	Current     npsResultStruct
+	Previous    *npsResultStruct
+	Delta       string
+	Chart       template.HTML
+	Histogram   template.HTML
+	Admin       bool
+	Reasons     []npsReasonGroup
+	Translation translation.Translation
+}
+
+const npsAdmin = `
+<h1>{{.Question}}</h1>
+{{.Result}}
+{{if not .Finished}}
+<p><button onclick="sendData('NPS', 'close')">{{.Translation.Finish}}</button></p>
+{{end}}
+`
+
+var npsAdminTemplate = template.Must(template.New("npsAdmin").Parse(npsAdmin))
+
+type npsAdminStruct struct {
+	Question    string
+	Result      template.HTML
+	Finished    bool
+	Translation translation.Translation
+}
+
+type npsGetConfig struct {
+	Question string
+	Reason   bool
+	Previous json.RawMessage
+}
+
+type npsAnswer struct {
+	Score  int
+	Reason string
+}
+
+type npsReasonGroup struct {
+	Label   string
+	Reasons []npsAnswer
+}
+
+// npsResultStruct is the downloaded result. It can be used as a previous result for comparison.
+type npsResultStruct struct {
+	Question   string
+	Answers    []int // Number of answers for each score from 0 to 10
+	Reasons    []npsAnswer
+	Promoters  int
+	Passives   int
+	Detractors int
+	Total      int
+	Score      float64
+}
+
+// calculate updates all derived values from Answers.
+func (r *npsResultStruct) calculate() {
+	r.Promoters, r.Passives, r.Detractors, r.Total = 0, 0, 0, 0
+	for i := range r.Answers {
+		switch {
+		case i >= npsMinPromoter:
+			r.Promoters += r.Answers[i]
+		case i >= npsMinPassive:
+			r.Passives += r.Answers[i]
+		default:
+			r.Detractors += r.Answers[i]
+		}
+		r.Total += r.Answers[i]
+	}
+	r.Score = 0
+	if r.Total > 0 {
+		r.Score = helper.RoundFloat(float64(r.Promoters-r.Detractors)/float64(r.Total)*100, 1)
+	}
+}
+
+// percentages returns the share of detractors, passives and promoters in percent.
+func (r *npsResultStruct) percentages() (float64, float64, float64) {
+	if r.Total == 0 {
+		return 0, 0, 0
+	}
+	t := float64(r.Total)
+	return float64(r.Detractors) / t * 100, float64(r.Passives) / t * 100, float64(r.Promoters) / t * 100
+}
+
+type nps struct {
+	adminHTML        chan<- template.HTML
+	userHTML         chan<- template.HTML
+	adminInput       <-chan []byte
+	userInput        <-chan []byte
+	participantInput <-chan registry.ParticipantInput
+	ctx              context.Context
+	cancel           context.CancelFunc
+
+	question     string
+	askReason    bool
+	previous     *npsResultStruct
+	answers      []int
+	reasons      []npsAnswer
+	participants map[string]bool
+	changed      bool
+	finished     bool
+	l            sync.Mutex
+}
+
+func (n *nps) ConfigHTML() (string, template.HTML) {
+	tl := translation.GetDefaultTranslation()
+	td := npsConfigStruct{
+		Translation: tl,
+	}
+	var buf bytes.Buffer
+	err := npsConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing nps config: %s", err.Error())
+	}
+
+	return tl.DisplayNPS, template.HTML(buf.Bytes())
+}
+
+func (n *nps) AdminHTMLChannel(c chan<- template.HTML) {
+	n.adminHTML = c
+}
+
+func (n *nps) UserHTMLChannel(c chan<- template.HTML) {
+	n.userHTML = c
+}
+
+func (n *nps) ReceiveUserChannel(c <-chan []byte) {
+	n.userInput = c
+}
+
+func (n *nps) ReceiveUserParticipantChannel(c <-chan registry.ParticipantInput) {
+	n.participantInput = c
+}
+
+func (n *nps) ReceiveAdminChannel(c <-chan []byte) {
+	n.adminInput = c
+}
+
+func (n *nps) Activate(b []byte) error {
+	var config npsGetConfig
+	err := json.Unmarshal(b, &config)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(config.Question) == "" {
+		return errors.New("nps: no question found")
+	}
+	n.question = config.Question
+	n.askReason = config.Reason
+
+	if len(config.Previous) != 0 && string(config.Previous) != "null" {
+		var previous npsResultStruct
+		err = json.Unmarshal(config.Previous, &previous)
+		if err != nil {
+			return fmt.Errorf("nps: can not read previous result: %w", err)
+		}
+		if len(previous.Answers) != npsMaxScore+1 {
+			return fmt.Errorf("nps: previous result must contain %d answers, found %d", npsMaxScore+1, len(previous.Answers))
+		}
+		for i := range previous.Answers {
+			if previous.Answers[i] < 0 {
+				return errors.New("nps: previous result contains negative answers")
+			}
+		}
+		previous.calculate()
+		n.previous = &previous
+	}
+
+	n.answers = make([]int, npsMaxScore+1)
+	n.participants = make(map[string]bool)
+
+	go func() {
+		n.userHTML <- n.GetLastHTMLUser()
+	}()
+	go func() {
+		n.adminHTML <- n.GetLastHTMLAdmin()
+	}()
+
+	n.ctx = context.Background()
+	n.ctx, n.cancel = context.WithCancel(n.ctx)
+	go func() {
+		done := n.ctx.Done()
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case b := <-n.adminInput:
+				n.l.Lock()
+				if string(b) == "close" && !n.finished {
+					n.finished = true
+					n.l.Unlock()
+					n.adminHTML <- n.GetLastHTMLAdmin()
+					n.userHTML <- n.GetLastHTMLUser()
+				} else {
+					n.l.Unlock()
+				}
+
+			case b := <-n.userInput:
+				n.receive(registry.ParticipantInput{Data: b})
+
+			case p := <-n.participantInput:
+				n.receive(p)
+
+			case <-ticker.C:
+				n.l.Lock()
+				changed := n.changed && !n.finished
+				n.changed = false
+				n.l.Unlock()
+
+				if changed {
+					n.adminHTML <- n.GetLastHTMLAdmin()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// receive handles an answer of a participant. Only the first answer of each participant is counted.
+func (n *nps) receive(p registry.ParticipantInput) {
+	var a npsAnswer
+	err := json.Unmarshal(p.Data, &a)
+	if err != nil || a.Score < 0 || a.Score > npsMaxScore {
+		return
+	}
+
+	n.l.Lock()
+	defer n.l.Unlock()
+
+	if n.finished || (p.Participant != "" && n.participants[p.Participant]) {
+		return
+	}
+	n.participants[p.Participant] = true
+	n.answers[a.Score]++
+
+	a.Reason = strings.TrimSpace(a.Reason)
+	if n.askReason && a.Reason != "" {
+		if len([]rune(a.Reason)) > npsMaxReasonLength {
+			a.Reason = string([]rune(a.Reason)[:npsMaxReasonLength])
+		}
+		n.reasons = append(n.reasons, a)
+	}
+	n.changed = true
+}
+
+// result returns the current result. Caller must hold l.
+func (n *nps) result() npsResultStruct {
+	r := npsResultStruct{
+		Question: n.question,
+		Answers:  make([]int, len(n.answers)),
+		Reasons:  make([]npsAnswer, len(n.reasons)),
+	}
+	copy(r.Answers, n.answers)
+	copy(r.Reasons, n.reasons)
+	r.calculate()
+	return r
+}
+
+func (n *nps) getResult(admin bool) template.HTML {
+	n.l.Lock()
+	defer n.l.Unlock()
+
+	tl := translation.GetDefaultTranslation()
+	td := npsResultTemplateStruct{
+		Current:     n.result(),
+		Previous:    n.previous,
+		Admin:       admin,
+		Translation: tl,
+	}
+
+	labels := []string{tl.Current}
+	detractors, passives, promoters := td.Current.percentages()
+	series := []helper.ChartSeries{
+		{Label: tl.Detractors, Colour: "#d9534f", Values: []float64{helper.RoundFloat(detractors, 1)}},
+		{Label: tl.Passives, Colour: "#f0ad4e", Values: []float64{helper.RoundFloat(passives, 1)}},
+		{Label: tl.Promoters, Colour: "#5cb85c", Values: []float64{helper.RoundFloat(promoters, 1)}},
+	}
+	if n.previous != nil {
+		labels = append(labels, tl.Previous)
+		detractors, passives, promoters = n.previous.percentages()
+		series[0].Values = append(series[0].Values, helper.RoundFloat(detractors, 1))
+		series[1].Values = append(series[1].Values, helper.RoundFloat(passives, 1))
+		series[2].Values = append(series[2].Values, helper.RoundFloat(promoters, 1))
+		delta := td.Current.Score - n.previous.Score
+		td.Delta = helper.FormatFloat(delta)
+		if delta > 0 {
+			td.Delta = "+" + td.Delta
+		}
+	}
+	td.Chart = helper.StackedBarChart(series, labels, "NPS_chart", "%", 100)
+
+	if admin {
+		v := make([]helper.ChartValue, len(n.answers))
+		for i := range n.answers {
+			v[i] = helper.ChartValue{Label: strconv.Itoa(i), Value: float64(n.answers[i])}
+		}
+		td.Histogram = helper.BarChart(v, "NPS_histogram", n.question)
+
+		td.Reasons = []npsReasonGroup{{Label: tl.Detractors}, {Label: tl.Passives}, {Label: tl.Promoters}}
+		for i := range n.reasons {
+			switch {
+			case n.reasons[i].Score >= npsMinPromoter:
+				td.Reasons[2].Reasons = append(td.Reasons[2].Reasons, n.reasons[i])
+			case n.reasons[i].Score >= npsMinPassive:
+				td.Reasons[1].Reasons = append(td.Reasons[1].Reasons, n.reasons[i])
+			default:
+				td.Reasons[0].Reasons = append(td.Reasons[0].Reasons, n.reasons[i])
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	err := npsResultTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing nps result: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (n *nps) GetLastHTMLUser() template.HTML {
+	n.l.Lock()
+	finished := n.finished
+	n.l.Unlock()
+
+	td := npsUserStruct{
+		Question:    n.question,
+		Scale:       make([]int, npsMaxScore+1),
+		Reason:      n.askReason,
+		MaxReason:   npsMaxReasonLength,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	for i := range td.Scale {
+		td.Scale[i] = i
+	}
+	if finished {
+		td.Result = n.getResult(false)
+	}
+
+	var buf bytes.Buffer
+	err := npsUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing nps user: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (n *nps) IsClosed() bool {
+	n.l.Lock()
+	defer n.l.Unlock()
+	return n.finished
+}
+
+func (n *nps) GetLastHTMLAdmin() template.HTML {
+	n.l.Lock()
+	finished := n.finished
+	n.l.Unlock()
+
+	td := npsAdminStruct{
+		Question:    n.question,
+		Result:      n.getResult(true),
+		Finished:    finished,
+		Translation: translation.GetDefaultTranslation(),
+	}
+
+	var buf bytes.Buffer
+	err := npsAdminTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing nps admin: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (n *nps) Deactivate() {
+	if n.cancel != nil {
+		n.cancel()
+	}
+}
+
+func (n *nps) GetAdminDownload() []byte {
+	n.l.Lock()
+	defer n.l.Unlock()
+
+	b, err := json.Marshal(n.result())
+	if err != nil {
+		return []byte(err.Error())
+	}
+	return b
+}
@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020,2023,2025 Marcus Soll
+// Copyright 2020,2023,2025,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"html/template"
 	"log"
+	"math"
 	"sort"
 	"strconv"
 	"sync"
@@ -39,16 +40,42 @@ func init() {
 	}
 }
 
+const (
+	numberDefaultBins = 10
+	numberMaxBins     = 100
+)
+
 const numberConfig = `
-<h1>%s</h1>
-<p>%s: <input id="Number" type="text"></p>
-<p><button onclick="sendActivate('Number', JSON.stringify({'q': document.getElementById('Number').value}))">%s</button></p>
-<p><button onclick="saveElement('Number', JSON.stringify({'q': document.getElementById('Number').value}), '%s: '+document.getElementById('Number').value)">%s</button></p>
+<h1>{{.Translation.DisplayNumber}}</h1>
+<p>{{.Translation.DisplayQuestion}}: <input id="Number" type="text"></p>
+<p>{{.Translation.Minimum}}: <input id="NumberMin" type="number" step="any"> {{.Translation.Maximum}}: <input id="NumberMax" type="number" step="any"></p>
+<p><input id="NumberDecimal" type="checkbox"> <label for="NumberDecimal">{{.Translation.AllowDecimal}}</label></p>
+<p>{{.Translation.TrimOutliers}}: <input id="NumberTrim" type="number" min="0" max="49" value="0"> %</p>
+<p>{{.Translation.HistogramBins}}: <input id="NumberBins" type="number" min="1" max="{{.MaxBins}}" value="10"></p>
+<p>{{.Translation.CorrectValue}}: <input id="NumberCorrect" type="number" step="any"></p>
+<p>{{.Translation.TimeLimit}}: <input id="NumberTimeLimit" type="number" min="0"> {{.Translation.Seconds}}</p>
+<p><button onclick="sendActivate('Number', numberGetData())">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('Number', numberGetData(), '{{.Translation.DisplayNumber}}: '+document.getElementById('Number').value)">{{.Translation.SaveElement}}</button></p>
+
+<script>
+function numberGetData() {
+	let data = {'q': document.getElementById('Number').value, 'min': document.getElementById('NumberMin').value, 'max': document.getElementById('NumberMax').value, 'd': document.getElementById('NumberDecimal').checked ? 'true' : '', 't': document.getElementById('NumberTrim').value, 'b': document.getElementById('NumberBins').value, 'c': document.getElementById('NumberCorrect').value, 'tl': document.getElementById('NumberTimeLimit').value};
+	return JSON.stringify(data);
+}
+</script>
 `
 
+var numberConfigTemplate = template.Must(template.New("numberConfig").Parse(numberConfig))
+
+type numberConfigStruct struct {
+	MaxBins     int
+	Translation translation.Translation
+}
+
 const numberUser = `
 <h1>{{.Question}}</h1>
-{{$.Translation.DisplayNumber}}: <input id="numberInput" type="number" onchange="document.getElementById('numberButton').disabled = document.getElementById('numberInput').value == ''">
+{{.TimeLimit}}
+{{$.Translation.DisplayNumber}}: <input id="numberInput" type="number" {{if .HasMin}}min="{{.Min}}"{{end}} {{if .HasMax}}max="{{.Max}}"{{end}} step="{{if .Decimal}}any{{else}}1{{end}}" onchange="document.getElementById('numberButton').disabled = document.getElementById('numberInput').value == ''">
 <button id="numberButton" onclick="if(!document.getElementById('numberInput').reportValidity()){return;};sendData('Number',document.getElementById('numberInput').value);document.getElementById('numberInput').disabled=true;document.getElementById('numberButton').disabled=true;" disabled>{{$.Translation.Submit}}</button>
 `
 
@@ -56,11 +83,51 @@ var numberUserTemplate = template.Must(template.New("numberUser").Parse(numberUs
 
 type numberUserStruct struct {
 	Question    string
+	HasMin      bool
+	Min         float64
+	HasMax      bool
+	Max         float64
+	Decimal     bool
+	TimeLimit   template.HTML
 	Translation translation.Translation
 }
 
+const numberStatistics = `
+{{define "statistics"}}
+<table style="border: none;">
+	<tr style="border: none;">
+		<td style="border: none;">{{.Translation.Mean}}</td>
+		<td style="border: none;">{{format .Statistics.Mean}}</td>
+	</tr>
+	<tr style="border: none;">
+		<td style="border: none;">{{.Translation.Median}}</td>
+		<td style="border: none;">{{format .Statistics.Median}}</td>
+	</tr>
+	<tr style="border: none;">
+		<td style="border: none;">{{.Translation.StandardDeviation}}</td>
+		<td style="border: none;">{{format .Statistics.StandardDeviation}}</td>
+	</tr>
+	<tr style="border: none;">
+		<td style="border: none;">{{.Translation.Percentile}} 10 / 25 / 75 / 90</td>
+		<td style="border: none;">{{format .Statistics.P10}} / {{format .Statistics.P25}} / {{format .Statistics.P75}} / {{format .Statistics.P90}}</td>
+	</tr>
+	<tr style="border: none;">
+		<td style="border: none;">{{.Translation.Minimum}} / {{.Translation.Maximum}}</td>
+		<td style="border: none;">{{format .Statistics.Min}} / {{format .Statistics.Max}}</td>
+	</tr>
+	{{if .Trimmed}}
+	<tr style="border: none;">
+		<td style="border: none;"><em>{{.Translation.Trimmed}}</em></td>
+		<td style="border: none;"><em>{{.Trimmed}}</em></td>
+	</tr>
+	{{end}}
+</table>
+{{end}}
+`
+
 const numberAdmin = `
 <h1>{{.Question}}</h1>
+{{.TimeLimit}}
 <table style="border: none;">
 {{range $i, $e := .Answers}}
     <tr style="border: none;">
@@ -73,21 +140,63 @@ const numberAdmin = `
 		<td style="border: none;"><em>{{.Submitted}}</em></td>
 	</tr>
 </table>
+{{if .Submitted}}
+{{template "statistics" .}}
+{{end}}
 <p><button onclick="sendData('Number', 'close')">{{.Translation.Finish}}</button></p>
 `
 
-var numberAdminTemplate = template.Must(template.New("numberAdmin").Parse(numberAdmin))
+var numberAdminTemplate = template.Must(template.Must(template.New("numberAdmin").Funcs(template.FuncMap{"format": helper.FormatFloat}).Parse(numberAdmin)).Parse(numberStatistics))
 
 type numberAdminStruct struct {
 	Question string
 	Answers  []struct {
-		Question int
+		Question string
 		Count    int
 	}
 	Submitted   int
+	Statistics  helper.Statistics
+	Trimmed     int
+	TimeLimit   template.HTML
 	Translation translation.Translation
 }
 
+const numberResult = `
+{{.Chart}}
+{{if .Submitted}}
+{{template "statistics" .}}
+{{end}}
+{{if .Revealed}}
+<h2>{{.Translation.CorrectValue}}: {{format .Correct}}</h2>
+<p>{{.Translation.Mean}}: {{format .Statistics.Mean}} ({{signed .MeanDeviation}}) - {{.Translation.Median}}: {{format .Statistics.Median}} ({{signed .MedianDeviation}})</p>
+{{else if .CanReveal}}
+<p><button onclick="sendData('Number', 'reveal')">{{.Translation.RevealCorrectValue}}</button></p>
+{{end}}
+`
+
+var numberResultTemplate = template.Must(template.Must(template.New("numberResult").Funcs(template.FuncMap{
+	"format": helper.FormatFloat,
+	"signed": func(f float64) string {
+		if f > 0 {
+			return "+" + helper.FormatFloat(f)
+		}
+		return helper.FormatFloat(f)
+	},
+}).Parse(numberResult)).Parse(numberStatistics))
+
+type numberResultStruct struct {
+	Chart           template.HTML
+	Submitted       int
+	Statistics      helper.Statistics
+	Trimmed         int
+	Revealed        bool
+	CanReveal       bool
+	Correct         float64
+	MeanDeviation   float64
+	MedianDeviation float64
+	Translation     translation.Translation
+}
+
 type number struct {
 	adminHTML  chan<- template.HTML
 	userHTML   chan<- template.HTML
@@ -97,16 +206,37 @@ type number struct {
 	cancel     context.CancelFunc
 
 	Question        string
-	NumberAnswers   map[int]int
+	HasMin          bool
+	Min             float64
+	HasMax          bool
+	Max             float64
+	Decimal         bool
+	TrimPercent     float64
+	Bins            int
+	HasCorrect      bool
+	Correct         float64
+	Revealed        bool
+	NumberAnswers   map[float64]int
+	Values          []float64
 	NumberSubmitted int
 	NumberChanged   bool
 	AnswerLock      sync.Mutex
 	Finished        bool
+	limit           timeLimit
 }
 
 func (n *number) ConfigHTML() (string, template.HTML) {
 	tl := translation.GetDefaultTranslation()
-	return tl.DisplayNumber, template.HTML(fmt.Sprintf(numberConfig, template.HTMLEscapeString(tl.DisplayNumber), template.HTMLEscapeString(tl.DisplayQuestion), template.HTMLEscapeString(tl.Activate), template.HTMLEscapeString(tl.DisplayNumber), template.HTMLEscapeString(tl.SaveElement)))
+	td := numberConfigStruct{
+		MaxBins:     numberMaxBins,
+		Translation: tl,
+	}
+	var buf bytes.Buffer
+	err := numberConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing number config: %s", err.Error())
+	}
+	return tl.DisplayNumber, template.HTML(buf.Bytes())
 }
 
 func (n *number) AdminHTMLChannel(c chan<- template.HTML) {
@@ -125,6 +255,22 @@ func (n *number) ReceiveAdminChannel(c <-chan []byte) {
 	n.adminInput = c
 }
 
+// numberParseOptional parses an optional number of the configuration.
+// An empty string is not an error, but returns false.
+func numberParseOptional(s, name string) (float64, bool, error) {
+	if s == "" {
+		return 0, false, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("can not parse %s: %w", name, err)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false, fmt.Errorf("%s must be a finite number", name)
+	}
+	return f, true, nil
+}
+
 func (n *number) Activate(b []byte) error {
 	input := make(map[string]string)
 	err := json.Unmarshal(b, &input)
@@ -137,19 +283,54 @@ func (n *number) Activate(b []byte) error {
 		return fmt.Errorf("no question found")
 	}
 
-	n.NumberAnswers = make(map[int]int)
+	n.Min, n.HasMin, err = numberParseOptional(input["min"], "minimum")
+	if err != nil {
+		return err
+	}
+	n.Max, n.HasMax, err = numberParseOptional(input["max"], "maximum")
+	if err != nil {
+		return err
+	}
+	if n.HasMin && n.HasMax && n.Min > n.Max {
+		return fmt.Errorf("minimum %s is larger than maximum %s", helper.FormatFloat(n.Min), helper.FormatFloat(n.Max))
+	}
 
-	go func() {
-		td := numberUserStruct{
-			Question:    n.Question,
-			Translation: translation.GetDefaultTranslation(),
-		}
-		var buf bytes.Buffer
-		err := numberUserTemplate.Execute(&buf, td)
+	n.Decimal = input["d"] != ""
+
+	n.TrimPercent, _, err = numberParseOptional(input["t"], "outlier trimming")
+	if err != nil {
+		return err
+	}
+	if n.TrimPercent < 0 || n.TrimPercent >= 50 {
+		return fmt.Errorf("outlier trimming must be between 0 and 50 percent")
+	}
+
+	n.Bins = numberDefaultBins
+	if input["b"] != "" {
+		n.Bins, err = strconv.Atoi(input["b"])
 		if err != nil {
-			log.Printf("error executing numberUser: %s", err.Error())
+			return fmt.Errorf("can not parse histogram bins: %w", err)
 		}
-		n.userHTML <- template.HTML(buf.Bytes())
+		if n.Bins <= 0 || n.Bins > numberMaxBins {
+			return fmt.Errorf("histogram bins must be between 1 and %d", numberMaxBins)
+		}
+	}
+
+	n.Correct, n.HasCorrect, err = numberParseOptional(input["c"], "correct value")
+	if err != nil {
+		return err
+	}
+
+	n.NumberAnswers = make(map[float64]int)
+
+	d, err := parseTimeLimit(input)
+	if err != nil {
+		return err
+	}
+	timeout := n.limit.Start(d)
+
+	go func() {
+		n.userHTML <- n.getUserPage()
 	}()
 	go func() {
 		n.adminHTML <- n.getAdminPage()
@@ -164,24 +345,34 @@ func (n *number) Activate(b []byte) error {
 		for {
 			select {
 			case b := <-n.adminInput:
-				n.AnswerLock.Lock()
-				if string(b) == "close" && !n.Finished {
-					n.Finished = true
-					n.AnswerLock.Unlock()
-					t := n.numberGetChart()
-					n.adminHTML <- t
-					n.userHTML <- t
-				} else {
-					n.AnswerLock.Unlock()
+				switch string(b) {
+				case "close":
+					n.finish()
+				case "reveal":
+					n.AnswerLock.Lock()
+					if n.Finished && n.HasCorrect && !n.Revealed {
+						n.Revealed = true
+						n.AnswerLock.Unlock()
+						n.adminHTML <- n.numberGetChart(true)
+						n.userHTML <- n.numberGetChart(false)
+					} else {
+						n.AnswerLock.Unlock()
+					}
 				}
 
+			case <-timeout:
+				n.finish()
+
 			case b := <-n.userInput:
-				i, err := strconv.Atoi(string(b))
-				if err == nil {
+				f, ok := n.parseAnswer(string(b))
+				if ok {
 					n.AnswerLock.Lock()
-					n.NumberAnswers[i]++
-					n.NumberSubmitted++
-					n.NumberChanged = true
+					if !n.Finished {
+						n.NumberAnswers[f]++
+						n.Values = append(n.Values, f)
+						n.NumberSubmitted++
+						n.NumberChanged = true
+					}
 					n.AnswerLock.Unlock()
 				}
 
@@ -203,30 +394,42 @@ func (n *number) Activate(b []byte) error {
 	return nil
 }
 
+// parseAnswer validates an answer of a participant against the configuration.
+func (n *number) parseAnswer(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	if !n.Decimal && f != math.Trunc(f) {
+		return 0, false
+	}
+	if n.HasMin && f < n.Min {
+		return 0, false
+	}
+	if n.HasMax && f > n.Max {
+		return 0, false
+	}
+	return f, true
+}
+
 func (n *number) GetLastHTMLUser() template.HTML {
 
 	if n.Finished {
-		return n.numberGetChart()
+		return n.numberGetChart(false)
 	}
 
+	return n.getUserPage()
+}
+
+func (n *number) IsClosed() bool {
 	n.AnswerLock.Lock()
 	defer n.AnswerLock.Unlock()
-
-	td := numberUserStruct{
-		Question:    n.Question,
-		Translation: translation.GetDefaultTranslation(),
-	}
-	var buf bytes.Buffer
-	err := numberUserTemplate.Execute(&buf, td)
-	if err != nil {
-		log.Printf("error executing numberUser: %s", err.Error())
-	}
-	return template.HTML(buf.Bytes())
+	return n.Finished
 }
 
 func (n *number) GetLastHTMLAdmin() template.HTML {
 	if n.Finished {
-		return n.numberGetChart()
+		return n.numberGetChart(true)
 	}
 	return n.getAdminPage()
 }
@@ -235,53 +438,108 @@ func (n *number) Deactivate() {
 	if n.cancel != nil {
 		n.cancel()
 	}
+	n.limit.Stop()
+}
+
+// finish closes the question and sends the results to everyone.
+// It does nothing if the question is already closed.
+func (n *number) finish() {
+	n.AnswerLock.Lock()
+	if n.Finished {
+		n.AnswerLock.Unlock()
+		return
+	}
+	n.Finished = true
+	n.AnswerLock.Unlock()
+
+	n.limit.Stop()
+	n.adminHTML <- n.numberGetChart(true)
+	n.userHTML <- n.numberGetChart(false)
 }
 
-func (n *number) numberGetChart() template.HTML {
+func (n *number) getUserPage() template.HTML {
 	n.AnswerLock.Lock()
 	defer n.AnswerLock.Unlock()
 
-	keys := make([]int, 0, len(n.NumberAnswers))
-	for k := range n.NumberAnswers {
-		keys = append(keys, k)
+	td := numberUserStruct{
+		Question:    n.Question,
+		HasMin:      n.HasMin,
+		Min:         n.Min,
+		HasMax:      n.HasMax,
+		Max:         n.Max,
+		Decimal:     n.Decimal,
+		TimeLimit:   n.limit.HTML(),
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := numberUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing numberUser: %s", err.Error())
 	}
+	return template.HTML(buf.Bytes())
+}
 
-	sort.Sort(sort.IntSlice(keys))
+// trimmedValues returns the sorted values after outlier trimming.
+// Caller must hold AnswerLock.
+func (n *number) trimmedValues() []float64 {
+	return helper.TrimOutliers(n.Values, n.TrimPercent)
+}
 
-	v := make([]helper.ChartValue, len(keys))
+func (n *number) numberGetChart(admin bool) template.HTML {
+	n.AnswerLock.Lock()
+	defer n.AnswerLock.Unlock()
 
-	for i := range keys {
-		v[i].Label = strconv.Itoa(keys[i])
-		v[i].Value = float64(n.NumberAnswers[keys[i]])
+	v := n.trimmedValues()
+	td := numberResultStruct{
+		Chart:       helper.BarChart(helper.Histogram(v, n.Bins, !n.Decimal), "numberChart", n.Question),
+		Submitted:   n.NumberSubmitted,
+		Statistics:  helper.CalculateStatistics(v),
+		Trimmed:     len(n.Values) - len(v),
+		Revealed:    n.HasCorrect && n.Revealed,
+		CanReveal:   admin && n.HasCorrect,
+		Correct:     n.Correct,
+		Translation: translation.GetDefaultTranslation(),
 	}
-	return helper.BarChart(v, "numberChart", n.Question)
+	td.MeanDeviation = td.Statistics.Mean - n.Correct
+	td.MedianDeviation = td.Statistics.Median - n.Correct
+
+	var buf bytes.Buffer
+	err := numberResultTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing numberResult: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
 }
 
 func (n *number) getAdminPage() template.HTML {
 	n.AnswerLock.Lock()
 	defer n.AnswerLock.Unlock()
 
-	keys := make([]int, 0, len(n.NumberAnswers))
+	keys := make([]float64, 0, len(n.NumberAnswers))
 	for k := range n.NumberAnswers {
 		keys = append(keys, k)
 	}
 
-	sort.Sort(sort.IntSlice(keys))
+	sort.Float64s(keys)
 
+	v := n.trimmedValues()
 	td := numberAdminStruct{
 		Question: n.Question,
 		Answers: make([]struct {
-			Question int
+			Question string
 			Count    int
 		}, 0, len(n.NumberAnswers)),
 		Submitted:   n.NumberSubmitted,
+		Statistics:  helper.CalculateStatistics(v),
+		Trimmed:     len(n.Values) - len(v),
+		TimeLimit:   n.limit.HTML(),
 		Translation: translation.GetDefaultTranslation(),
 	}
 	for i := range keys {
 		td.Answers = append(td.Answers, struct {
-			Question int
+			Question string
 			Count    int
-		}{keys[i], n.NumberAnswers[keys[i]]})
+		}{strconv.FormatFloat(keys[i], 'f', -1, 64), n.NumberAnswers[keys[i]]})
 	}
 
 	var buf bytes.Buffer
@@ -292,11 +550,38 @@ func (n *number) getAdminPage() template.HTML {
 	return template.HTML(buf.Bytes())
 }
 
+type numberDownloadStruct struct {
+	Question    string
+	Answers     map[string]int
+	Submitted   int
+	TrimPercent float64
+	Trimmed     int
+	Statistics  helper.Statistics
+	Correct     *float64 `json:",omitempty"`
+}
+
 func (n *number) GetAdminDownload() []byte {
 	n.AnswerLock.Lock()
 	defer n.AnswerLock.Unlock()
 
-	b, err := json.Marshal(n.NumberAnswers)
+	v := n.trimmedValues()
+	d := numberDownloadStruct{
+		Question:    n.Question,
+		Answers:     make(map[string]int, len(n.NumberAnswers)),
+		Submitted:   n.NumberSubmitted,
+		TrimPercent: n.TrimPercent,
+		Trimmed:     len(n.Values) - len(v),
+		Statistics:  helper.CalculateStatistics(v),
+	}
+	for k := range n.NumberAnswers {
+		d.Answers[strconv.FormatFloat(k, 'f', -1, 64)] = n.NumberAnswers[k]
+	}
+	if n.HasCorrect {
+		c := n.Correct
+		d.Correct = &c
+	}
+
+	b, err := json.Marshal(d)
 	if err != nil {
 		return []byte(err.Error())
 	}
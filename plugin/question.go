@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020,2023,2025 Marcus Soll
+// Copyright 2020,2023,2025,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -19,7 +19,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"html/template"
 	"log"
 	"strconv"
@@ -39,56 +38,62 @@ func init() {
 }
 
 const questionConfig = `
-<h1>%s</h1>
-<p>%s: <input id="Question" type="text"></p>
-<table style="border: none;">
-    <tr style="border: none; background-color: inherit;">
-        <td style="border: none;"><input id="Question_1" type="text"></td>
-        <td style="border: none;"><input id="Question_2" type="text"></td>
-        <td style="border: none;"><input id="Question_3" type="text"></td>
-    </tr>
-    <tr style="border: none; background-color: inherit;">
-        <td style="border: none;"><input id="Question_4" type="text"></td>
-        <td style="border: none;"><input id="Question_5" type="text"></td>
-        <td style="border: none;"><input id="Question_6" type="text"></td>
-    </tr>
-    <tr style="border: none; background-color: inherit;">
-        <td style="border: none;"><input id="Question_7" type="text"></td>
-        <td style="border: none;"><input id="Question_8" type="text"></td>
-        <td style="border: none;"><input id="Question_9" type="text"></td>
-    </tr>
-</table>
-<p><button onclick="sendActivate('Question', JSON.stringify({'1': document.getElementById('Question_1').value, '2': document.getElementById('Question_2').value, '3': document.getElementById('Question_3').value, '4': document.getElementById('Question_4').value, '5': document.getElementById('Question_5').value, '6': document.getElementById('Question_6').value, '7': document.getElementById('Question_7').value, '8': document.getElementById('Question_8').value, '9': document.getElementById('Question_9').value, 'q': document.getElementById('Question').value}))">%s</button></p>
-<p><button onclick="saveElement('Question', JSON.stringify({'1': document.getElementById('Question_1').value, '2': document.getElementById('Question_2').value, '3': document.getElementById('Question_3').value, '4': document.getElementById('Question_4').value, '5': document.getElementById('Question_5').value, '6': document.getElementById('Question_6').value, '7': document.getElementById('Question_7').value, '8': document.getElementById('Question_8').value, '9': document.getElementById('Question_9').value, 'q': document.getElementById('Question').value}), '%s: '+document.getElementById('Question').value)">%s</button></p>
+<h1>{{.Translation.DisplayQuestion}}</h1>
+<p>{{.Translation.DisplayQuestion}}: <input id="Question" type="text"></p>
+<h2>{{.Translation.Answers}}</h2>
+<ol id="Question_options"></ol>
+<p><button onclick="choiceAddOption('Question', '', false)">{{.Translation.Add}}</button></p>
+<p>{{.Translation.TimeLimit}}: <input id="Question_tl" type="number" min="0"> {{.Translation.Seconds}}</p>
+<p>{{.Translation.RevealMode}}: <select id="Question_r"><option value="">{{.Translation.RevealAdmin}}</option><option value="live">{{.Translation.RevealLive}}</option><option value="hidden">{{.Translation.RevealHidden}}</option><option value="never">{{.Translation.RevealNever}}</option></select></p>
+<p><button onclick="sendActivate('Question', choiceGetData('Question'))">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('Question', choiceGetData('Question'), '{{.Translation.DisplayQuestion}}: '+document.getElementById('Question').value)">{{.Translation.SaveElement}}</button></p>
+` + choiceConfigScript + `
+<script>
+choiceAddOption('Question', '', false);
+choiceAddOption('Question', '', false);
+choiceAddOption('Question', '', false);
+</script>
 `
 
+var questionConfigTemplate = template.Must(template.New("questionConfig").Parse(questionConfig))
+
+type questionConfigStruct struct {
+	Translation translation.Translation
+}
+
 const questionUser = `
 <h1>{{.Question}}</h1>
+{{.TimeLimit}}
 <table style="border: none;">
 {{range $i, $e := .Answers}}
     <tr style="border: none;">
         <td id="Question_cell_{{$i}}" style="border: none;">{{$e}}</td>
-		<td style="border: none;"><button id="Question_button_{{$i}}" onclick="sendData('Question','{{$i}}');document.getElementById('Question_cell_{{$i}}').style.backgroundColor='var(--primary-colour-dark)';var e=document.getElementById('Question_button_0'); if(e!=null){e.disabled=true;};var e=document.getElementById('Question_button_1'); if(e!=null){e.disabled=true;};var e=document.getElementById('Question_button_2'); if(e!=null){e.disabled=true;};var e=document.getElementById('Question_button_3'); if(e!=null){e.disabled=true;};var e=document.getElementById('Question_button_4'); if(e!=null){e.disabled=true;};var e=document.getElementById('Question_button_5'); if(e!=null){e.disabled=true;};var e=document.getElementById('Question_button_6'); if(e!=null){e.disabled=true;};var e=document.getElementById('Question_button_7'); if(e!=null){e.disabled=true;};var e=document.getElementById('Question_button_8'); if(e!=null){e.disabled=true;};">{{$.Translation.Submit}}</button></td>
+		<td style="border: none;"><button id="Question_button_{{$i}}" class="Question_button" onclick="sendData('Question','{{$i}}');document.getElementById('Question_cell_{{$i}}').style.backgroundColor='var(--primary-colour-dark)';document.querySelectorAll('.Question_button').forEach(function(e){e.disabled=true;});">{{$.Translation.Submit}}</button></td>
+		{{if $.Live}}<td id="Question_count_{{$i}}" style="border: none;">{{index $.Counts $i}}</td>{{end}}
 	</tr>
 {{end}}
 </table>
-`
+` + choiceLiveScript
 
 var questionUserTemplate = template.Must(template.New("questionUser").Parse(questionUser))
 
 type questionUserStruct struct {
 	Question    string
-	Answers     []string
+	Answers     []template.HTML
+	Counts      []int
+	Live        bool
+	TimeLimit   template.HTML
 	Translation translation.Translation
 }
 
 const questionAdmin = `
 <h1>{{.Question}}</h1>
+{{.TimeLimit}}
 <table style="border: none;">
 {{range $i, $e := .Answers}}
     <tr style="border: none;">
         <td style="border: none;">{{$e.Question}}</td>
-		<td style="border: none;">{{$e.Count}}</td>
+		{{if not $.Hidden}}<td style="border: none;">{{$e.Count}}</td>{{end}}
 	</tr>
 {{end}}
 	<tr style="border: none;">
@@ -104,10 +109,12 @@ var questionAdminTemplate = template.Must(template.New("questionAdmin").Parse(qu
 type questionAdminStruct struct {
 	Question string
 	Answers  []struct {
-		Question string
+		Question template.HTML
 		Count    int
 	}
 	Submitted   int
+	Hidden      bool
+	TimeLimit   template.HTML
 	Translation translation.Translation
 }
 
@@ -116,21 +123,34 @@ type question struct {
 	userHTML   chan<- template.HTML
 	adminInput <-chan []byte
 	userInput  <-chan []byte
+	adminData  chan<- []byte
+	userData   chan<- []byte
 	ctx        context.Context
 	cancel     context.CancelFunc
 
 	Question        string
 	QuestionAnswers []string
+	answerHTML      []template.HTML
 	AnswerCount     []int
 	NumberSubmitted int
 	NumberChanged   bool
 	AnswerLock      sync.Mutex
 	Finished        bool
+	limit           timeLimit
+	reveal          revealMode
 }
 
 func (q *question) ConfigHTML() (string, template.HTML) {
 	tl := translation.GetDefaultTranslation()
-	return tl.DisplayQuestion, template.HTML(fmt.Sprintf(questionConfig, template.HTMLEscapeString(tl.DisplayQuestion), template.HTMLEscapeString(tl.DisplayQuestion), template.HTMLEscapeString(tl.Activate), template.HTMLEscapeString(tl.DisplayQuestion), template.HTMLEscapeString(tl.SaveElement)))
+	td := questionConfigStruct{
+		Translation: tl,
+	}
+	var buf bytes.Buffer
+	err := questionConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing question config: %s", err.Error())
+	}
+	return tl.DisplayQuestion, template.HTML(buf.Bytes())
 }
 
 func (q *question) AdminHTMLChannel(c chan<- template.HTML) {
@@ -149,43 +169,34 @@ func (q *question) ReceiveAdminChannel(c <-chan []byte) {
 	q.adminInput = c
 }
 
+func (q *question) AdminDataChannel(c chan<- []byte) {
+	q.adminData = c
+}
+
+func (q *question) UserDataChannel(c chan<- []byte) {
+	q.userData = c
+}
+
 func (q *question) Activate(b []byte) error {
-	input := make(map[string]string)
-	err := json.Unmarshal(b, &input)
+	c, err := parseChoiceConfig(b)
 	if err != nil {
 		return err
 	}
 
-	q.Question = input["q"]
-	if q.Question == "" {
-		return fmt.Errorf("no question found")
+	q.Question = c.Question
+	q.QuestionAnswers = make([]string, len(c.Options))
+	q.answerHTML = make([]template.HTML, len(c.Options))
+	for i := range c.Options {
+		q.QuestionAnswers[i] = c.Options[i].Text
+		q.answerHTML[i] = c.Options[i].HTML()
 	}
-
-	for i := 1; i <= 9; i++ {
-		s := input[fmt.Sprintf("%d", i)]
-		if s != "" {
-			q.QuestionAnswers = append(q.QuestionAnswers, s)
-		}
-	}
-
-	if len(q.QuestionAnswers) == 0 {
-		return fmt.Errorf("no answers found")
-	}
-
 	q.AnswerCount = make([]int, len(q.QuestionAnswers))
+	q.reveal = c.Reveal
+
+	timeout := q.limit.Start(c.TimeLimit)
 
 	go func() {
-		td := questionUserStruct{
-			Question:    q.Question,
-			Answers:     q.QuestionAnswers,
-			Translation: translation.GetDefaultTranslation(),
-		}
-		var buf bytes.Buffer
-		err := questionUserTemplate.Execute(&buf, td)
-		if err != nil {
-			log.Printf("error executing questionUser: %s", err.Error())
-		}
-		q.userHTML <- template.HTML(buf.Bytes())
+		q.userHTML <- q.getUserPage()
 	}()
 	go func() {
 		q.adminHTML <- q.getAdminPage()
@@ -200,22 +211,18 @@ func (q *question) Activate(b []byte) error {
 		for {
 			select {
 			case b := <-q.adminInput:
-				q.AnswerLock.Lock()
-				if string(b) == "close" && !q.Finished {
-					q.Finished = true
-					q.AnswerLock.Unlock()
-					t := q.questionGetChart()
-					q.adminHTML <- t
-					q.userHTML <- t
-				} else {
-					q.AnswerLock.Unlock()
+				if string(b) == "close" {
+					q.finish()
 				}
 
+			case <-timeout:
+				q.finish()
+
 			case b := <-q.userInput:
 				i, err := strconv.Atoi(string(b))
 				if err == nil {
 					q.AnswerLock.Lock()
-					if i < len(q.AnswerCount) {
+					if !q.Finished && i >= 0 && i < len(q.AnswerCount) {
 						q.AnswerCount[i]++
 						q.NumberSubmitted++
 						q.NumberChanged = true
@@ -232,6 +239,9 @@ func (q *question) Activate(b []byte) error {
 
 				if !finished && changed {
 					q.adminHTML <- q.getAdminPage()
+					if q.reveal.userLive() {
+						q.sendCounts()
+					}
 				}
 			case <-done:
 				return
@@ -242,29 +252,24 @@ func (q *question) Activate(b []byte) error {
 }
 
 func (q *question) GetLastHTMLUser() template.HTML {
-
-	if q.Finished {
+	if q.IsClosed() {
+		if !q.reveal.userFinal() {
+			return choiceClosedPage(q.Question)
+		}
 		return q.questionGetChart()
 	}
 
+	return q.getUserPage()
+}
+
+func (q *question) IsClosed() bool {
 	q.AnswerLock.Lock()
 	defer q.AnswerLock.Unlock()
-
-	td := questionUserStruct{
-		Question:    q.Question,
-		Answers:     q.QuestionAnswers,
-		Translation: translation.GetDefaultTranslation(),
-	}
-	var buf bytes.Buffer
-	err := questionUserTemplate.Execute(&buf, td)
-	if err != nil {
-		log.Printf("error executing questionUser: %s", err.Error())
-	}
-	return template.HTML(buf.Bytes())
+	return q.Finished
 }
 
 func (q *question) GetLastHTMLAdmin() template.HTML {
-	if q.Finished {
+	if q.IsClosed() {
 		return q.questionGetChart()
 	}
 	return q.getAdminPage()
@@ -274,6 +279,59 @@ func (q *question) Deactivate() {
 	if q.cancel != nil {
 		q.cancel()
 	}
+	q.limit.Stop()
+}
+
+// finish closes the question and sends the results to everyone.
+// It does nothing if the question is already closed.
+func (q *question) finish() {
+	q.AnswerLock.Lock()
+	if q.Finished {
+		q.AnswerLock.Unlock()
+		return
+	}
+	q.Finished = true
+	q.AnswerLock.Unlock()
+
+	q.limit.Stop()
+	t := q.questionGetChart()
+	q.adminHTML <- t
+	if !q.reveal.userFinal() {
+		t = choiceClosedPage(q.Question)
+	}
+	q.userHTML <- t
+}
+
+// sendCounts sends the current number of answers to the participants.
+func (q *question) sendCounts() {
+	q.AnswerLock.Lock()
+	b, err := json.Marshal(q.AnswerCount)
+	q.AnswerLock.Unlock()
+	if err != nil {
+		log.Printf("question: Error marshaling counts: (%s)", err.Error())
+		return
+	}
+	q.userData <- b
+}
+
+func (q *question) getUserPage() template.HTML {
+	q.AnswerLock.Lock()
+	defer q.AnswerLock.Unlock()
+
+	td := questionUserStruct{
+		Question:    q.Question,
+		Answers:     q.answerHTML,
+		Counts:      q.AnswerCount,
+		Live:        q.reveal.userLive(),
+		TimeLimit:   q.limit.HTML(),
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := questionUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing questionUser: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
 }
 
 func (q *question) questionGetChart() template.HTML {
@@ -296,17 +354,19 @@ func (q *question) getAdminPage() template.HTML {
 	td := questionAdminStruct{
 		Question: q.Question,
 		Answers: make([]struct {
-			Question string
+			Question template.HTML
 			Count    int
 		}, 0, len(q.QuestionAnswers)),
 		Submitted:   q.NumberSubmitted,
+		Hidden:      !q.reveal.adminLive(),
+		TimeLimit:   q.limit.HTML(),
 		Translation: translation.GetDefaultTranslation(),
 	}
 	for i := range q.AnswerCount {
 		td.Answers = append(td.Answers, struct {
-			Question string
+			Question template.HTML
 			Count    int
-		}{q.QuestionAnswers[i], q.AnswerCount[i]})
+		}{q.answerHTML[i], q.AnswerCount[i]})
 	}
 
 	var buf bytes.Buffer
@@ -320,14 +380,20 @@ func (q *question) getAdminPage() template.HTML {
 type questionResultStruct struct {
 	Question        string
 	QuestionAnswers []string
-	AnswerCount     []int
+	AnswerCount     []int `json:",omitempty"`
+	Submitted       int
 }
 
 func (q *question) GetAdminDownload() []byte {
 	q.AnswerLock.Lock()
 	defer q.AnswerLock.Unlock()
 
-	b, err := json.Marshal(questionResultStruct{Question: q.Question, QuestionAnswers: q.QuestionAnswers, AnswerCount: q.AnswerCount})
+	r := questionResultStruct{Question: q.Question, QuestionAnswers: q.QuestionAnswers, AnswerCount: q.AnswerCount, Submitted: q.NumberSubmitted}
+	if !q.reveal.adminLive() && !q.Finished {
+		// Results are hidden from the admin until the question is closed
+		r.AnswerCount = nil
+	}
+	b, err := json.Marshal(r)
 	if err != nil {
 		return []byte(err.Error())
 	}
@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2023,2025 Marcus Soll
+// Copyright 2023,2025,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -20,8 +20,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"html/template"
 	"log"
+	"math/rand"
 	"strconv"
 	"strings"
 	"sync"
@@ -50,12 +52,13 @@ const randomgroupConfig = `
 3
 </textarea><br>
 <label for="RandomGroupSeperator">{{.Translation.Seperator}}:</label> <input id="RandomGroupSeperator" type="text" value="<<<--->>>"><br>
+<label for="RandomGroupCapacities">{{.Translation.GroupCapacities}}:</label> <input id="RandomGroupCapacities" type="text" placeholder="5, 5, 4"><br>
 <p><button onclick="sendActivate('RandomGroup', randomgroupGetData())">{{.Translation.Activate}}</button></p>
 <p><button onclick="saveElement('RandomGroup', randomgroupGetData(), '{{.Translation.DisplayRandomGroup}}: '+document.getElementById('RandomGroupTitle').value.substring(0,80)+(document.getElementById('RandomGroupTitle').value.length>80?'[...]':''))">{{.Translation.SaveElement}}</button></p>
 
 <script>
 function randomgroupGetData() {
-	let data = {"Title": document.getElementById('RandomGroupTitle').value, "Text": document.getElementById('RandomGroupTextarea').value, "Seperator": document.getElementById('RandomGroupSeperator').value};
+	let data = {"Title": document.getElementById('RandomGroupTitle').value, "Text": document.getElementById('RandomGroupTextarea').value, "Seperator": document.getElementById('RandomGroupSeperator').value, "Capacities": document.getElementById('RandomGroupCapacities').value};
 	return JSON.stringify(data);
 }
 </script>
@@ -69,7 +72,7 @@ type randomgroupConfigStruct struct {
 
 const randomgroupUser = `
 <h1>{{.Headline}}</h1>
-<div id="RandomGroup"></div>
+<div id="RandomGroup"><p><em>{{.Translation.GroupWaiting}}</em></p></div>
 
 <script>
 var randomGroup = [
@@ -78,9 +81,20 @@ var randomGroup = [
 {{end}}
 ];
 
-var randomGroupSelection = Math.floor(Math.random() * randomGroup.length);
-document.getElementById("RandomGroup").innerHTML = randomGroup[randomGroupSelection];
-sendDataSilent("RandomGroup", ""+randomGroupSelection);
+data_function = function(d) {
+	let g = parseInt(d);
+	let e = document.getElementById("RandomGroup");
+	if(e === null) {
+		return;
+	}
+	if(isNaN(g) || g < 0 || g >= randomGroup.length) {
+		e.innerHTML = "<p><em>{{.Translation.GroupsFull}}</em></p>";
+		return;
+	}
+	e.innerHTML = randomGroup[g];
+};
+
+sendDataSilent("RandomGroup", "join");
 </script>`
 
 var randomgroupUserTemplate = template.Must(template.New("randomgroupConfig").Parse(randomgroupUser))
@@ -96,67 +110,94 @@ const randomgroupAdmin = `
 
 {{.Chart}}
 
-{{range $i, $e := .Answers}}
-<h2>{{addOne $i}}</h2>
+<p><button onclick="sendData('RandomGroup', JSON.stringify({'Action': 'reshuffle'}))">{{.Translation.Reshuffle}}</button></p>
+
+{{range $i, $e := .Groups}}
+<h2>{{addOne $i}}{{if $e.Capacity}} ({{len $e.Members}} / {{$e.Capacity}}){{else}} ({{len $e.Members}}){{end}}</h2>
 <div style="background-color: white;margin: 10px;">
-{{$e}}
+{{$e.Text}}
 </div>
+{{if $e.Members}}<p>{{range $j, $m := $e.Members}}{{if $j}}, {{end}}P{{addOne $m}}{{end}}</p>{{end}}
 {{end}}
 
+{{if .Unassigned}}
+<h2>{{.Translation.WithoutGroup}}</h2>
+<p>{{range $j, $m := .Unassigned}}{{if $j}}, {{end}}P{{addOne $m}}{{end}}</p>
+{{end}}
 
-<script>
-function updateChart(b) {
-	try {
-	 var data = JSON.parse(b);
-	 console.log(data)
-	 chartData.data.datasets[0].data = data.Data;
-	 chart.update();
-	} catch (e) {
-	 console.log(e);
-	}
- };
-
-data_function = function(b) {
-	setTimeout(updateChart(b), 1000);
-};
-</script>
+{{if .Participants}}
+<h2>{{.Translation.Assignment}}</h2>
+<table>
+{{range $i, $g := .Participants}}
+<tr><td>P{{addOne $i}}</td><td><select onchange="sendData('RandomGroup', JSON.stringify({'Action': 'assign', 'Participant': {{$i}}, 'Group': parseInt(this.value)}))">
+<option value="-1"{{if eq $g -1}} selected{{end}}>-</option>
+{{range $j, $e := $.Groups}}<option value="{{$j}}"{{if eq $g $j}} selected{{end}}>{{addOne $j}}</option>{{end}}
+</select></td></tr>
+{{end}}
+</table>
+{{end}}
 `
 
 var randomgroupAdminTemplate = template.Must(template.New("randomtextConfig").Funcs(template.FuncMap{"addOne": func(i int) int { return i + 1 }}).Parse(randomgroupAdmin))
 
+type randomgroupAdminGroup struct {
+	Text     template.HTML
+	Capacity int
+	Members  []int
+}
+
 type randomgroupAdminStruct struct {
-	Headline    string
-	Chart       template.HTML
-	Answers     []template.HTML
-	Translation translation.Translation
+	Headline     string
+	Chart        template.HTML
+	Groups       []randomgroupAdminGroup
+	Unassigned   []int
+	Participants []int
+	Translation  translation.Translation
+}
+
+type randomgroupAdminCommand struct {
+	Action      string
+	Participant int
+	Group       int
+}
+
+type randomgroupDownloadAssignment struct {
+	Participant string
+	Group       int
 }
 
-type randomgroupAdminChartUpdate struct {
-	Data []int
+type randomgroupDownload struct {
+	Counts      []int
+	Capacities  []int
+	Assignments []randomgroupDownloadAssignment
 }
 
 type randomgroup struct {
-	adminHTML  chan<- template.HTML
-	userHTML   chan<- template.HTML
-	adminInput <-chan []byte
-	userInput  <-chan []byte
-	adminData  chan<- []byte
-	userData   chan<- []byte
-	ctx        context.Context
-	cancel     context.CancelFunc
-
-	numAnswers     int
-	userSelectMap  map[int]int
-	userHTMLcache  template.HTML
-	adminHTMLcache template.HTML
+	adminHTML        chan<- template.HTML
+	userHTML         chan<- template.HTML
+	adminInput       <-chan []byte
+	userInput        <-chan []byte
+	participantInput <-chan registry.ParticipantInput
+	participantData  chan<- registry.ParticipantOutput
+	ctx              context.Context
+	cancel           context.CancelFunc
+
+	headline      string
+	texts         []template.HTML
+	capacities    []int          // 0 means unlimited
+	participants  []string       // participants in order of joining
+	assignment    map[string]int // participant -> group, -1 if no group is available
+	userHTMLcache template.HTML
+	changed       bool
 
 	l sync.Mutex
 }
 
 type randomgroupGetConfig struct {
-	Title     string
-	Text      string
-	Seperator string
+	Title      string
+	Text       string
+	Seperator  string
+	Capacities string
 }
 
 func (rg *randomgroup) ConfigHTML() (string, template.HTML) {
@@ -185,16 +226,52 @@ func (rg *randomgroup) ReceiveUserChannel(c <-chan []byte) {
 	rg.userInput = c
 }
 
+func (rg *randomgroup) ReceiveUserParticipantChannel(c <-chan registry.ParticipantInput) {
+	rg.participantInput = c
+}
+
+func (rg *randomgroup) UserParticipantDataChannel(c chan<- registry.ParticipantOutput) {
+	rg.participantData = c
+}
+
 func (rg *randomgroup) ReceiveAdminChannel(c <-chan []byte) {
 	rg.adminInput = c
 }
 
-func (rg *randomgroup) AdminDataChannel(c chan<- []byte) {
-	rg.adminData = c
-}
+// parseCapacities parses a comma separated list of group capacities.
+// A single value is used for all groups, an empty value or 0 means unlimited.
+func parseCapacities(s string, groups int) ([]int, error) {
+	c := make([]int, groups)
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return c, nil
+	}
 
-func (rg *randomgroup) UserDataChannel(c chan<- []byte) {
-	rg.userData = c
+	split := strings.Split(s, ",")
+	if len(split) != 1 && len(split) != groups {
+		return nil, fmt.Errorf("randomgroup: %d capacities given for %d groups", len(split), groups)
+	}
+	for i := range split {
+		v := 0
+		if t := strings.TrimSpace(split[i]); t != "" {
+			var err error
+			v, err = strconv.Atoi(t)
+			if err != nil {
+				return nil, fmt.Errorf("randomgroup: invalid capacity '%s'", t)
+			}
+			if v < 0 {
+				return nil, fmt.Errorf("randomgroup: capacity must not be negative (%d)", v)
+			}
+		}
+		if len(split) == 1 {
+			for j := range c {
+				c[j] = v
+			}
+			break
+		}
+		c[i] = v
+	}
+	return c, nil
 }
 
 func (rg *randomgroup) Activate(b []byte) error {
@@ -222,10 +299,14 @@ func (rg *randomgroup) Activate(b []byte) error {
 		return errors.New("randomgroup: at least one group must have valid text")
 	}
 
-	rg.numAnswers = len(split)
+	rg.capacities, err = parseCapacities(config.Capacities, len(split))
+	if err != nil {
+		return err
+	}
 
-	// Create map
-	rg.userSelectMap = make(map[int]int, len(split))
+	rg.headline = config.Title
+	rg.texts = split
+	rg.assignment = make(map[string]int)
 
 	// User HTML
 	{
@@ -242,30 +323,10 @@ func (rg *randomgroup) Activate(b []byte) error {
 		}
 		rg.userHTMLcache = template.HTML(buf.Bytes())
 	}
-	// Admin HTML
-	{
-		cv := make([]helper.ChartValue, len(split))
-		for i := range split {
-			cv[i].Label = strconv.Itoa(i + 1)
-		}
-		td := randomgroupAdminStruct{
-			Headline:    config.Title,
-			Chart:       helper.PieChart(cv, "RandomGroup", tl.DisplayRandomGroup),
-			Answers:     split,
-			Translation: tl,
-		}
-		var buf bytes.Buffer
-		err := randomgroupAdminTemplate.Execute(&buf, td)
-		if err != nil {
-			log.Printf("error executing randomgroup admin: %s", err.Error())
-			return err
-		}
-		rg.adminHTMLcache = template.HTML(buf.Bytes())
-	}
 
 	// Start plugin
 	go func() { rg.userHTML <- rg.userHTMLcache }()
-	go func() { rg.adminHTML <- rg.adminHTMLcache }()
+	go func() { rg.adminHTML <- rg.GetLastHTMLAdmin() }()
 	rg.ctx = context.Background()
 	rg.ctx, rg.cancel = context.WithCancel(rg.ctx)
 	go rg.worker(rg.ctx)
@@ -279,7 +340,43 @@ func (rg *randomgroup) GetLastHTMLUser() template.HTML {
 }
 
 func (rg *randomgroup) GetLastHTMLAdmin() template.HTML {
-	return rg.adminHTMLcache
+	rg.l.Lock()
+	defer rg.l.Unlock()
+
+	tl := translation.GetDefaultTranslation()
+	td := randomgroupAdminStruct{
+		Headline:     rg.headline,
+		Groups:       make([]randomgroupAdminGroup, len(rg.texts)),
+		Participants: make([]int, len(rg.participants)),
+		Translation:  tl,
+	}
+	for i := range rg.texts {
+		td.Groups[i].Text = rg.texts[i]
+		td.Groups[i].Capacity = rg.capacities[i]
+	}
+	for i, p := range rg.participants {
+		g := rg.assignment[p]
+		td.Participants[i] = g
+		if g == -1 {
+			td.Unassigned = append(td.Unassigned, i)
+			continue
+		}
+		td.Groups[g].Members = append(td.Groups[g].Members, i)
+	}
+
+	cv := make([]helper.ChartValue, len(rg.texts))
+	for i := range cv {
+		cv[i].Label = strconv.Itoa(i + 1)
+		cv[i].Value = float64(len(td.Groups[i].Members))
+	}
+	td.Chart = helper.PieChart(cv, "RandomGroup", tl.DisplayRandomGroup)
+
+	var buf bytes.Buffer
+	err := randomgroupAdminTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing randomgroup admin: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
 }
 
 func (rg *randomgroup) Deactivate() {
@@ -288,37 +385,124 @@ func (rg *randomgroup) Deactivate() {
 	}
 }
 
+// counts returns the number of participants per group. Caller must hold l.
+func (rg *randomgroup) counts() []int {
+	c := make([]int, len(rg.texts))
+	for _, g := range rg.assignment {
+		if g >= 0 {
+			c[g]++
+		}
+	}
+	return c
+}
+
+// leastFilled returns the group with the fewest participants which still has free capacity.
+// Ties are broken by the lowest group index, so the assignment is deterministic.
+// Returns -1 if all groups are full.
+func leastFilled(counts, capacities []int) int {
+	best := -1
+	for i := range counts {
+		if capacities[i] != 0 && counts[i] >= capacities[i] {
+			continue
+		}
+		if best == -1 || counts[i] < counts[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// assign returns the group of the participant, assigning a new one if needed. Caller must hold l.
+func (rg *randomgroup) assign(p string) int {
+	g, ok := rg.assignment[p]
+	if ok && g != -1 {
+		return g
+	}
+	if !ok {
+		rg.participants = append(rg.participants, p)
+	}
+	g = leastFilled(rg.counts(), rg.capacities)
+	rg.assignment[p] = g
+	rg.changed = true
+	return g
+}
+
+// reshuffle assigns all participants in random order to new groups. Caller must hold l.
+func (rg *randomgroup) reshuffle() {
+	order := make([]string, len(rg.participants))
+	copy(order, rg.participants)
+	rand.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+	counts := make([]int, len(rg.texts))
+	for _, p := range order {
+		g := leastFilled(counts, rg.capacities)
+		rg.assignment[p] = g
+		if g >= 0 {
+			counts[g]++
+		}
+	}
+	rg.changed = true
+}
+
+// send sends the group of a participant to all connections of the participant.
+// Must not be called while holding l.
+func (rg *randomgroup) send(p string, g int) {
+	if p == "" {
+		return
+	}
+	rg.participantData <- registry.ParticipantOutput{Participant: p, Data: []byte(strconv.Itoa(g))}
+}
+
 func (rg *randomgroup) worker(ctx context.Context) {
 	done := rg.ctx.Done()
-	t := time.NewTicker(5 * time.Second)
+	t := time.NewTicker(time.Second)
 	for {
 		select {
-		case <-rg.adminInput:
-			// Do nothing since no action is here
-		case b := <-rg.userInput:
+		case b := <-rg.adminInput:
+			var c randomgroupAdminCommand
+			err := json.Unmarshal(b, &c)
+			if err != nil {
+				continue
+			}
+			update := make(map[string]int)
 			rg.l.Lock()
-			i, err := strconv.Atoi(string(b))
-			if err == nil && i < rg.numAnswers {
-				count, _ := rg.userSelectMap[i]
-				rg.userSelectMap[i] = count + 1
+			switch c.Action {
+			case "reshuffle":
+				rg.reshuffle()
+				for p, g := range rg.assignment {
+					update[p] = g
+				}
+			case "assign":
+				if c.Participant < 0 || c.Participant >= len(rg.participants) || c.Group < -1 || c.Group >= len(rg.texts) {
+					break
+				}
+				p := rg.participants[c.Participant]
+				rg.assignment[p] = c.Group
+				rg.changed = true
+				update[p] = c.Group
 			}
 			rg.l.Unlock()
-		case <-t.C:
-			data := randomgroupAdminChartUpdate{
-				Data: make([]int, rg.numAnswers),
+			for p, g := range update {
+				rg.send(p, g)
 			}
-			rg.l.Lock()
-			for i := 0; i < rg.numAnswers; i++ {
-				data.Data[i] = rg.userSelectMap[i]
+		case <-rg.userInput:
+			// Inputs without a participant can not be assigned to a group
+		case i := <-rg.participantInput:
+			if string(i.Data) != "join" || i.Participant == "" {
+				continue
 			}
+			rg.l.Lock()
+			g := rg.assign(i.Participant)
 			rg.l.Unlock()
-
-			b, err := json.Marshal(data)
-			if err != nil {
-				log.Printf("randogroup: Error marshaling update: (%s)", err.Error())
-				continue
+			rg.send(i.Participant, g)
+		case <-t.C:
+			rg.l.Lock()
+			changed := rg.changed
+			rg.changed = false
+			rg.l.Unlock()
+			if changed {
+				rg.adminHTML <- rg.GetLastHTMLAdmin()
 			}
-			rg.adminData <- b
 		case <-done:
 			t.Stop()
 			return
@@ -326,11 +510,33 @@ func (rg *randomgroup) worker(ctx context.Context) {
 	}
 }
 
+func (rg *randomgroup) GetGroups() map[string]string {
+	rg.l.Lock()
+	defer rg.l.Unlock()
+
+	groups := make(map[string]string, len(rg.assignment))
+	for p, g := range rg.assignment {
+		if g >= 0 {
+			groups[p] = strconv.Itoa(g + 1)
+		}
+	}
+	return groups
+}
+
 func (rg *randomgroup) GetAdminDownload() []byte {
 	rg.l.Lock()
 	defer rg.l.Unlock()
 
-	b, err := json.Marshal(rg.userSelectMap)
+	d := randomgroupDownload{
+		Counts:      rg.counts(),
+		Capacities:  rg.capacities,
+		Assignments: make([]randomgroupDownloadAssignment, len(rg.participants)),
+	}
+	for i, p := range rg.participants {
+		d.Assignments[i] = randomgroupDownloadAssignment{Participant: fmt.Sprintf("P%d", i+1), Group: rg.assignment[p] + 1}
+	}
+
+	b, err := json.Marshal(d)
 	if err != nil {
 		return []byte(err.Error())
 	}
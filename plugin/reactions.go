@@ -0,0 +1,515 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/responsego/helper"
+	"github.com/Top-Ranger/responsego/registry"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+func init() {
+	err := registry.RegisterFeedbackPlugin(func() registry.FeedbackPlugin { return new(reactions) }, "Reactions")
+	if err != nil {
+		panic(err)
+	}
+}
+
+const (
+	reactionsMaxEmoji      = 12
+	reactionsMaxEmojiBytes = 32
+	reactionsDefaultLimit  = 2
+	reactionsDefaultEmoji  = "👍 ❤️ 😂 😮 👏 🤔"
+	reactionsTimeLayout    = "2006-01-02T15:04:05"
+)
+
+const reactionsConfig = `
+<h1>{{.Translation.DisplayReactions}}</h1>
+<p>{{.Translation.Title}}: <input id="ReactionsTitle" type="text"></p>
+<p>{{.Translation.ReactionsEmoji}}: <input id="ReactionsEmoji" type="text" value="{{.DefaultEmoji}}"></p>
+<p>{{.Translation.ReactionsLimit}}: <input id="ReactionsLimit" type="number" min="1" max="10" value="{{.DefaultLimit}}"></p>
+<p><button onclick="sendActivate('Reactions', reactionsGetData())">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('Reactions', reactionsGetData(), '{{.Translation.DisplayReactions}}: '+document.getElementById('ReactionsEmoji').value)">{{.Translation.SaveElement}}</button></p>
+
+<script>
+function reactionsGetData() {
+	return JSON.stringify({"Title": document.getElementById('ReactionsTitle').value, "Emoji": document.getElementById('ReactionsEmoji').value.split(/\s+/).filter(function(e) { return e !== ""; }), "Limit": parseInt(document.getElementById('ReactionsLimit').value)});
+}
+</script>
+`
+
+var reactionsConfigTemplate = template.Must(template.New("reactionsConfig").Parse(reactionsConfig))
+
+type reactionsConfigStruct struct {
+	DefaultEmoji string
+	DefaultLimit int
+	Translation  translation.Translation
+}
+
+const reactionsUser = `
+<h1>{{.Title}}</h1>
+<div class="centre">
+{{range $i, $e := .Emoji}}
+<button class="reactionbutton" onclick="sendDataSilent('Reactions', '{{$i}}')">{{$e}}</button>
+{{end}}
+</div>
+`
+
+var reactionsUserTemplate = template.Must(template.New("reactionsUser").Parse(reactionsUser))
+
+type reactionsUserStruct struct {
+	Title       string
+	Emoji       []string
+	Translation translation.Translation
+}
+
+const reactionsAdmin = `
+<h1>{{.Title}}</h1>
+<div class="reactionsarea" id="reactionsArea"></div>
+<table>
+<tr>{{range $i, $e := .Emoji}}<th>{{$e}}</th>{{end}}</tr>
+<tr>{{range $i, $e := .Totals}}<td id="reactionsTotal{{$i}}">{{$e}}</td>{{end}}</tr>
+</table>
+<div class="chart barchart">
+	<canvas id="reactionsChart"></canvas>
+</div>
+
+<script>
+var reactionsEmoji = {{.Emoji}};
+var reactionsTotals = {{.Totals}};
+var reactionsTimeline = {{.Timeline}};
+
+var reactionsChart = new Chart(document.getElementById('reactionsChart').getContext('2d'), {
+	type: "line",
+	data: {
+		datasets: reactionsEmoji.map(function(e, i) {
+			return {
+				label: e,
+				data: reactionsTimeline.map(function(b) { return {x: b.Time, y: b.Counts[i]}; }),
+			};
+		}),
+	},
+	options: {
+		plugins: {
+			title: {
+				display: true,
+				text: "{{.Translation.Timeline}}"
+			}
+		},
+		responsive: true,
+		animation: false,
+		scales: {
+			x: {
+				type: 'time',
+				time: {
+					tooltipFormat: 'HH:mm:ss',
+					displayFormats: {
+						second: 'HH:mm:ss',
+						minute: 'HH:mm',
+					}
+				}
+			},
+			y: {
+				beginAtZero: true,
+				ticks: {
+					precision: 0
+				}
+			}
+		},
+	}
+});
+
+function reactionsFloat(emoji) {
+	let area = document.getElementById("reactionsArea");
+	if(area === null) {
+		return;
+	}
+	let e = document.createElement("SPAN");
+	e.classList.add("reactionsfloat");
+	e.textContent = emoji;
+	e.style.left = (Math.random() * 90) + "%";
+	e.style.animationDelay = (Math.random() * 0.8) + "s";
+	e.addEventListener("animationend", function() {
+		e.remove();
+	});
+	area.appendChild(e);
+}
+
+data_function = function(d) {
+	let b = JSON.parse(d);
+	for(let i = 0; i < b.Counts.length; i++) {
+		reactionsTotals[i] += b.Counts[i];
+		let t = document.getElementById("reactionsTotal" + i);
+		if(t !== null) {
+			t.textContent = reactionsTotals[i];
+		}
+		for(let j = 0; j < Math.min(b.Counts[i], {{.MaxFloating}}); j++) {
+			reactionsFloat(reactionsEmoji[i]);
+		}
+		reactionsChart.data.datasets[i].data.push({x: b.Time, y: b.Counts[i]});
+	}
+	reactionsChart.update();
+};
+</script>
+`
+
+var reactionsAdminTemplate = template.Must(template.New("reactionsAdmin").Parse(reactionsAdmin))
+
+type reactionsAdminStruct struct {
+	Title       string
+	Emoji       []string
+	Totals      []int
+	Timeline    []reactionsBucket
+	MaxFloating int
+	Translation translation.Translation
+}
+
+const reactionsDownload = `
+<h1>{{.Title}}</h1>
+{{.Timeline}}
+{{.Totals}}
+`
+
+var reactionsDownloadTemplate = template.Must(template.New("reactionsDownload").Parse(reactionsDownload))
+
+type reactionsDownloadStruct struct {
+	Title    string
+	Timeline template.HTML
+	Totals   template.HTML
+}
+
+type reactionsGetConfig struct {
+	Title string
+	Emoji []string
+	Limit int
+}
+
+// reactionsBucket holds the number of reactions per emoji in a single second.
+type reactionsBucket struct {
+	Time   string
+	Counts []int
+}
+
+type reactions struct {
+	adminHTML        chan<- template.HTML
+	userHTML         chan<- template.HTML
+	adminInput       <-chan []byte
+	userInput        <-chan []byte
+	participantInput <-chan registry.ParticipantInput
+	adminData        chan<- []byte
+	userData         chan<- []byte
+	ctx              context.Context
+	cancel           context.CancelFunc
+
+	title    string
+	emoji    []string
+	limit    int
+	current  []int
+	totals   []int
+	timeline []reactionsBucket
+	perUser  map[string]int // reactions of each participant in the current second
+	l        sync.Mutex
+}
+
+func (r *reactions) ConfigHTML() (string, template.HTML) {
+	tl := translation.GetDefaultTranslation()
+	td := reactionsConfigStruct{
+		DefaultEmoji: reactionsDefaultEmoji,
+		DefaultLimit: reactionsDefaultLimit,
+		Translation:  tl,
+	}
+	var buf bytes.Buffer
+	err := reactionsConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing reactions config: %s", err.Error())
+	}
+
+	return tl.DisplayReactions, template.HTML(buf.Bytes())
+}
+
+func (r *reactions) AdminHTMLChannel(c chan<- template.HTML) {
+	r.adminHTML = c
+}
+
+func (r *reactions) UserHTMLChannel(c chan<- template.HTML) {
+	r.userHTML = c
+}
+
+func (r *reactions) ReceiveUserChannel(c <-chan []byte) {
+	r.userInput = c
+}
+
+func (r *reactions) ReceiveUserParticipantChannel(c <-chan registry.ParticipantInput) {
+	r.participantInput = c
+}
+
+func (r *reactions) ReceiveAdminChannel(c <-chan []byte) {
+	r.adminInput = c
+}
+
+func (r *reactions) AdminDataChannel(c chan<- []byte) {
+	r.adminData = c
+}
+
+func (r *reactions) UserDataChannel(c chan<- []byte) {
+	r.userData = c
+}
+
+func (r *reactions) Activate(b []byte) error {
+	var config reactionsGetConfig
+	err := json.Unmarshal(b, &config)
+	if err != nil {
+		return err
+	}
+
+	emoji := make([]string, 0, len(config.Emoji))
+	for _, e := range config.Emoji {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if len(e) > reactionsMaxEmojiBytes {
+			return fmt.Errorf("reactions: '%s' is too long", e)
+		}
+		emoji = append(emoji, e)
+	}
+	if len(emoji) == 0 {
+		return errors.New("reactions: no emoji found")
+	}
+	if len(emoji) > reactionsMaxEmoji {
+		return fmt.Errorf("reactions: at most %d emoji allowed", reactionsMaxEmoji)
+	}
+	if config.Limit <= 0 {
+		config.Limit = reactionsDefaultLimit
+	}
+
+	r.title = config.Title
+	r.emoji = emoji
+	r.limit = config.Limit
+	r.current = make([]int, len(emoji))
+	r.totals = make([]int, len(emoji))
+	r.timeline = make([]reactionsBucket, 0)
+	r.perUser = make(map[string]int)
+
+	go func() {
+		r.userHTML <- r.GetLastHTMLUser()
+	}()
+	go func() {
+		r.adminHTML <- r.GetLastHTMLAdmin()
+	}()
+
+	r.ctx = context.Background()
+	r.ctx, r.cancel = context.WithCancel(r.ctx)
+	go r.worker(r.ctx)
+	return nil
+}
+
+// receive counts a single reaction. Each participant may react at most limit times per second, additional reactions are dropped.
+func (r *reactions) receive(p registry.ParticipantInput) {
+	i, err := strconv.Atoi(string(p.Data))
+	if err != nil {
+		return
+	}
+
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	if i < 0 || i >= len(r.emoji) {
+		return
+	}
+	if r.perUser[p.Participant] >= r.limit {
+		return
+	}
+	r.perUser[p.Participant]++
+	r.current[i]++
+}
+
+func (r *reactions) worker(ctx context.Context) {
+	done := ctx.Done()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.adminInput:
+			// Do nothing since no action is here
+		case b := <-r.userInput:
+			r.receive(registry.ParticipantInput{Data: b})
+		case p := <-r.participantInput:
+			r.receive(p)
+		case t := <-ticker.C:
+			r.l.Lock()
+			r.perUser = make(map[string]int)
+			empty := true
+			for i := range r.current {
+				if r.current[i] != 0 {
+					empty = false
+					break
+				}
+			}
+			if empty {
+				r.l.Unlock()
+				continue
+			}
+			bucket := reactionsBucket{Time: t.Format(reactionsTimeLayout), Counts: r.current}
+			for i := range r.current {
+				r.totals[i] += r.current[i]
+			}
+			r.timeline = append(r.timeline, bucket)
+			r.current = make([]int, len(r.emoji))
+			r.l.Unlock()
+
+			b, err := json.Marshal(bucket)
+			if err != nil {
+				log.Printf("reactions: error marshaling update: %s", err.Error())
+				continue
+			}
+			r.adminData <- b
+		case <-done:
+			return
+		}
+	}
+}
+
+func (r *reactions) GetLastHTMLUser() template.HTML {
+	td := reactionsUserStruct{
+		Title:       r.title,
+		Emoji:       r.emoji,
+		Translation: translation.GetDefaultTranslation(),
+	}
+
+	var buf bytes.Buffer
+	err := reactionsUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing reactions user: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (r *reactions) GetLastHTMLAdmin() template.HTML {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	td := reactionsAdminStruct{
+		Title:       r.title,
+		Emoji:       r.emoji,
+		Totals:      make([]int, len(r.totals)),
+		Timeline:    make([]reactionsBucket, len(r.timeline)),
+		MaxFloating: 10,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	copy(td.Totals, r.totals)
+	copy(td.Timeline, r.timeline)
+
+	var buf bytes.Buffer
+	err := reactionsAdminTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing reactions admin: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (r *reactions) Deactivate() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
+// GetAdminDownload returns the timeline as CSV with one row per second containing reactions.
+func (r *reactions) GetAdminDownload() []byte {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(append([]string{"time"}, r.emoji...))
+	for _, b := range r.timeline {
+		row := make([]string, 0, len(b.Counts)+1)
+		row = append(row, b.Time)
+		for _, c := range b.Counts {
+			row = append(row, strconv.Itoa(c))
+		}
+		w.Write(row)
+	}
+	row := make([]string, 0, len(r.totals)+1)
+	row = append(row, "total")
+	for _, c := range r.totals {
+		row = append(row, strconv.Itoa(c))
+	}
+	w.Write(row)
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return []byte(err.Error())
+	}
+	return buf.Bytes()
+}
+
+// DownloadFormats returns the additional download formats. "html" contains the timeline as a graph.
+func (r *reactions) DownloadFormats() []string {
+	return []string{"html"}
+}
+
+// GetAdminDownloadFormat returns the timeline of all reactions per second and the totals per emoji as charts.
+func (r *reactions) GetAdminDownloadFormat(format string) []byte {
+	if format != "html" {
+		return nil
+	}
+
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	tl := translation.GetDefaultTranslation()
+	timeline := make([]helper.TimeValue, 0, len(r.timeline))
+	for _, b := range r.timeline {
+		t, err := time.Parse(reactionsTimeLayout, b.Time)
+		if err != nil {
+			continue
+		}
+		sum := 0
+		for _, c := range b.Counts {
+			sum += c
+		}
+		timeline = append(timeline, helper.TimeValue{Time: t, Value: float64(sum)})
+	}
+	totals := make([]helper.ChartValue, len(r.emoji))
+	for i := range r.emoji {
+		totals[i] = helper.ChartValue{Label: r.emoji[i], Value: float64(r.totals[i])}
+	}
+
+	td := reactionsDownloadStruct{
+		Title:    r.title,
+		Timeline: helper.TimeHistogram(timeline, time.Second, "reactionsTimeline", tl.Timeline, false),
+		Totals:   helper.BarChart(totals, "reactionsTotals", tl.DisplayReactions),
+	}
+	var buf bytes.Buffer
+	err := reactionsDownloadTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing reactions download: %s", err.Error())
+	}
+	return buf.Bytes()
+}
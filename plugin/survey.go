@@ -0,0 +1,789 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/responsego/helper"
+	"github.com/Top-Ranger/responsego/registry"
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+func init() {
+	err := registry.RegisterFeedbackPlugin(func() registry.FeedbackPlugin { return new(survey) }, "Survey")
+	if err != nil {
+		panic(err)
+	}
+}
+
+const (
+	surveySingle = "single"
+	surveyMulti  = "multi"
+	surveyNumber = "number"
+	surveyText   = "text"
+	surveyScale  = "scale"
+)
+
+const (
+	surveyMaxItems      = 50
+	surveyMaxTextLength = 2000
+	surveyMaxScaleSteps = 11
+	surveyMaxScaleValue = 1000 // minimum and maximum of a scale must be within ±surveyMaxScaleValue
+)
+
+const surveyConfig = `
+<h1>{{.Translation.DisplaySurvey}}</h1>
+<p>{{.Translation.Title}}: <input id="SurveyTitle" type="text"></p>
+<ol id="SurveyItems"></ol>
+<p>
+<select id="SurveyNewType">
+<option value="single">{{.Translation.SurveySingle}}</option>
+<option value="multi">{{.Translation.SurveyMulti}}</option>
+<option value="number">{{.Translation.SurveyNumber}}</option>
+<option value="text">{{.Translation.SurveyText}}</option>
+<option value="scale">{{.Translation.SurveyScale}}</option>
+</select>
+<button onclick="surveyAddItem({'Type': document.getElementById('SurveyNewType').value, 'Question': '', 'Options': [], 'Min': 1, 'Max': 5})">{{.Translation.Add}}</button>
+</p>
+<p><button onclick="sendActivate('Survey', surveyGetData())">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('Survey', surveyGetData(), '{{.Translation.DisplaySurvey}}: '+document.getElementById('SurveyTitle').value)">{{.Translation.SaveElement}}</button></p>
+
+<script>
+var surveyTypeNames = {
+	"single": "{{.Translation.SurveySingle}}",
+	"multi": "{{.Translation.SurveyMulti}}",
+	"number": "{{.Translation.SurveyNumber}}",
+	"text": "{{.Translation.SurveyText}}",
+	"scale": "{{.Translation.SurveyScale}}",
+};
+
+function surveyAddItem(item) {
+	let li = document.createElement("LI");
+	li.dataset.type = item.Type;
+	let type = document.createElement("STRONG");
+	type.textContent = surveyTypeNames[item.Type] + " ";
+	li.appendChild(type);
+	let question = document.createElement("INPUT");
+	question.type = "text";
+	question.placeholder = "{{.Translation.DisplayQuestion}}";
+	question.value = item.Question;
+	question.classList.add("SurveyQuestion");
+	li.appendChild(question);
+	if(item.Type === "single" || item.Type === "multi") {
+		li.appendChild(document.createElement("BR"));
+		let options = document.createElement("TEXTAREA");
+		options.placeholder = "{{.Translation.SurveyOptions}}";
+		options.value = item.Options.join("\n");
+		options.classList.add("SurveyOptions");
+		li.appendChild(options);
+	}
+	if(item.Type === "scale") {
+		li.appendChild(document.createElement("BR"));
+		let min = document.createElement("INPUT");
+		min.type = "number";
+		min.min = -{{.MaxScaleValue}};
+		min.max = {{.MaxScaleValue}};
+		min.value = item.Min;
+		min.classList.add("SurveyMin");
+		li.appendChild(document.createTextNode("{{.Translation.Minimum}}: "));
+		li.appendChild(min);
+		let max = document.createElement("INPUT");
+		max.type = "number";
+		max.min = -{{.MaxScaleValue}};
+		max.max = {{.MaxScaleValue}};
+		max.value = item.Max;
+		max.classList.add("SurveyMax");
+		li.appendChild(document.createTextNode(" {{.Translation.Maximum}}: "));
+		li.appendChild(max);
+	}
+	let remove = document.createElement("BUTTON");
+	remove.textContent = "{{.Translation.Remove}}";
+	remove.onclick = function() {
+		li.remove();
+	};
+	li.appendChild(remove);
+	document.getElementById("SurveyItems").appendChild(li);
+}
+
+function surveyGetData() {
+	let items = [];
+	document.querySelectorAll("#SurveyItems li").forEach(function(li) {
+		let item = {"Type": li.dataset.type, "Question": li.querySelector(".SurveyQuestion").value, "Options": [], "Min": 0, "Max": 0};
+		let options = li.querySelector(".SurveyOptions");
+		if(options !== null) {
+			item.Options = options.value.split("\n").map(function(o) { return o.trim(); }).filter(function(o) { return o !== ""; });
+		}
+		let min = li.querySelector(".SurveyMin");
+		if(min !== null) {
+			item.Min = parseInt(min.value);
+			item.Max = parseInt(li.querySelector(".SurveyMax").value);
+		}
+		items.push(item);
+	});
+	return JSON.stringify({"Title": document.getElementById('SurveyTitle').value, "Items": items});
+}
+</script>
+`
+
+var surveyConfigTemplate = template.Must(template.New("surveyConfig").Parse(surveyConfig))
+
+type surveyConfigStruct struct {
+	MaxScaleValue int
+	Translation   translation.Translation
+}
+
+const surveyUser = `
+<h1>{{.Title}}</h1>
+{{if .Finished}}
+<p>{{.Translation.SurveyClosed}}</p>
+{{else}}
+<div id="survey"></div>
+
+<script>
+var surveyItems = {{.Items}};
+var surveyAnswers = surveyItems.map(function() { return null; });
+var surveyPage = 0;
+
+function surveyCollect() {
+	let item = surveyItems[surveyPage];
+	let answer = null;
+	switch(item.Type) {
+	case "single":
+		document.querySelectorAll(".SurveyInput").forEach(function(e) {
+			if(e.checked) {
+				answer = parseInt(e.value);
+			}
+		});
+		break;
+	case "multi":
+		answer = [];
+		document.querySelectorAll(".SurveyInput").forEach(function(e) {
+			if(e.checked) {
+				answer.push(parseInt(e.value));
+			}
+		});
+		break;
+	case "number":
+		let n = parseFloat(document.getElementById("SurveyInputValue").value);
+		answer = isNaN(n) ? null : n;
+		break;
+	case "text":
+		answer = document.getElementById("SurveyInputValue").value;
+		break;
+	case "scale":
+		document.querySelectorAll(".SurveyInput").forEach(function(e) {
+			if(e.checked) {
+				answer = parseInt(e.value);
+			}
+		});
+		break;
+	}
+	surveyAnswers[surveyPage] = answer;
+}
+
+function surveyChoice(type, name, value, label, checked) {
+	let l = document.createElement("LABEL");
+	let input = document.createElement("INPUT");
+	input.type = type;
+	input.name = name;
+	input.value = value;
+	input.checked = checked;
+	input.classList.add("SurveyInput");
+	l.appendChild(input);
+	l.appendChild(document.createTextNode(" " + label));
+	return l;
+}
+
+function surveyRender() {
+	let s = document.getElementById("survey");
+	if(s === null) {
+		return;
+	}
+	s.innerHTML = "";
+	let item = surveyItems[surveyPage];
+	let answer = surveyAnswers[surveyPage];
+
+	let progress = document.createElement("P");
+	progress.innerHTML = "<em></em>";
+	progress.firstChild.textContent = (surveyPage + 1) + " / " + surveyItems.length;
+	s.appendChild(progress);
+	let h = document.createElement("H2");
+	h.textContent = item.Question;
+	s.appendChild(h);
+
+	switch(item.Type) {
+	case "single":
+	case "multi":
+		for(let i = 0; i < item.Options.length; i++) {
+			let checked = item.Type === "single" ? answer === i : (answer !== null && answer.includes(i));
+			s.appendChild(surveyChoice(item.Type === "single" ? "radio" : "checkbox", "SurveyInput", i, item.Options[i], checked));
+			s.appendChild(document.createElement("BR"));
+		}
+		break;
+	case "number":
+		let n = document.createElement("INPUT");
+		n.type = "number";
+		n.step = "any";
+		n.id = "SurveyInputValue";
+		n.value = answer === null ? "" : answer;
+		s.appendChild(n);
+		break;
+	case "text":
+		let t = document.createElement("TEXTAREA");
+		t.id = "SurveyInputValue";
+		t.maxLength = {{.MaxText}};
+		t.value = answer === null ? "" : answer;
+		s.appendChild(t);
+		break;
+	case "scale":
+		let d = document.createElement("DIV");
+		d.classList.add("npsscale");
+		for(let i = item.Min; i <= item.Max; i++) {
+			d.appendChild(surveyChoice("radio", "SurveyInput", i, String(i), answer === i));
+		}
+		s.appendChild(d);
+		break;
+	}
+
+	let p = document.createElement("P");
+	if(surveyPage > 0) {
+		let back = document.createElement("BUTTON");
+		back.textContent = "{{.Translation.SurveyBack}}";
+		back.onclick = function() {
+			surveyCollect();
+			surveyPage--;
+			surveyRender();
+		};
+		p.appendChild(back);
+	}
+	if(surveyPage < surveyItems.length - 1) {
+		let next = document.createElement("BUTTON");
+		next.textContent = "{{.Translation.SurveyNext}}";
+		next.onclick = function() {
+			surveyCollect();
+			surveyPage++;
+			surveyRender();
+		};
+		p.appendChild(next);
+	} else {
+		let submit = document.createElement("BUTTON");
+		submit.textContent = "{{.Translation.Submit}}";
+		submit.onclick = function() {
+			surveyCollect();
+			sendData('Survey', JSON.stringify({"Answers": surveyAnswers}));
+			s.innerHTML = "";
+			let done = document.createElement("P");
+			done.textContent = "{{.Translation.ResponseSent}}";
+			s.appendChild(done);
+		};
+		p.appendChild(submit);
+	}
+	s.appendChild(p);
+}
+
+surveyRender();
+</script>
+{{end}}
+`
+
+var surveyUserTemplate = template.Must(template.New("surveyUser").Parse(surveyUser))
+
+type surveyUserStruct struct {
+	Title       string
+	Items       []surveyItem
+	MaxText     int
+	Finished    bool
+	Translation translation.Translation
+}
+
+const surveyAdmin = `
+<h1>{{.Title}}</h1>
+<p><em>{{.Translation.Submitted}}: {{len .Table}}</em></p>
+{{if not .Finished}}
+<p><button onclick="sendData('Survey', 'close')">{{.Translation.Finish}}</button></p>
+{{end}}
+{{range $i, $e := .Summaries}}
+<h2>{{$e.Question}}</h2>
+{{if $e.Statistics}}
+<p>{{$.Translation.Mean}}: {{$e.Statistics.Mean}}, {{$.Translation.Median}}: {{$e.Statistics.Median}}, n = {{$e.Statistics.N}}</p>
+{{end}}
+{{$e.Chart}}
+{{end}}
+<h2>{{.Translation.AllResponses}}</h2>
+<table>
+<tr><th>#</th>{{range $i, $e := .Items}}<th>{{$e.Question}}</th>{{end}}</tr>
+{{range $i, $r := .Table}}
+<tr><td>{{addOne $i}}</td>{{range $j, $c := $r}}<td>{{$c}}</td>{{end}}</tr>
+{{end}}
+</table>
+`
+
+var surveyAdminTemplate = template.Must(template.New("surveyAdmin").Funcs(template.FuncMap{"addOne": func(i int) int { return i + 1 }}).Parse(surveyAdmin))
+
+type surveySummary struct {
+	Question   string
+	Statistics *surveyStatistics
+	Chart      template.HTML
+}
+
+type surveyStatistics struct {
+	N      int
+	Mean   string
+	Median string
+}
+
+type surveyAdminStruct struct {
+	Title       string
+	Items       []surveyItem
+	Summaries   []surveySummary
+	Table       [][]string
+	Finished    bool
+	Translation translation.Translation
+}
+
+type surveyItem struct {
+	Type     string
+	Question string
+	Options  []string
+	Min      int
+	Max      int
+}
+
+type surveyGetConfig struct {
+	Title string
+	Items []surveyItem
+}
+
+type surveyAnswer struct {
+	Answers []json.RawMessage
+}
+
+// surveyResponse holds the answers of a single participant.
+// Each answer is nil if not given, an int for single choice and scale, []int for multiple choice, float64 for numbers and a string for free text.
+type surveyResponse []interface{}
+
+type survey struct {
+	adminHTML        chan<- template.HTML
+	userHTML         chan<- template.HTML
+	adminInput       <-chan []byte
+	userInput        <-chan []byte
+	participantInput <-chan registry.ParticipantInput
+	ctx              context.Context
+	cancel           context.CancelFunc
+
+	title     string
+	items     []surveyItem
+	responses []surveyResponse
+	index     map[string]int // participant -> index in responses
+	changed   bool
+	finished  bool
+	l         sync.Mutex
+}
+
+func (s *survey) ConfigHTML() (string, template.HTML) {
+	tl := translation.GetDefaultTranslation()
+	td := surveyConfigStruct{
+		MaxScaleValue: surveyMaxScaleValue,
+		Translation:   tl,
+	}
+	var buf bytes.Buffer
+	err := surveyConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing survey config: %s", err.Error())
+	}
+
+	return tl.DisplaySurvey, template.HTML(buf.Bytes())
+}
+
+func (s *survey) AdminHTMLChannel(c chan<- template.HTML) {
+	s.adminHTML = c
+}
+
+func (s *survey) UserHTMLChannel(c chan<- template.HTML) {
+	s.userHTML = c
+}
+
+func (s *survey) ReceiveUserChannel(c <-chan []byte) {
+	s.userInput = c
+}
+
+func (s *survey) ReceiveUserParticipantChannel(c <-chan registry.ParticipantInput) {
+	s.participantInput = c
+}
+
+func (s *survey) ReceiveAdminChannel(c <-chan []byte) {
+	s.adminInput = c
+}
+
+func (s *survey) Activate(b []byte) error {
+	var config surveyGetConfig
+	err := json.Unmarshal(b, &config)
+	if err != nil {
+		return err
+	}
+
+	if len(config.Items) == 0 {
+		return errors.New("survey: no items found")
+	}
+	if len(config.Items) > surveyMaxItems {
+		return fmt.Errorf("survey: at most %d items allowed", surveyMaxItems)
+	}
+	for i := range config.Items {
+		item := &config.Items[i]
+		if strings.TrimSpace(item.Question) == "" {
+			return fmt.Errorf("survey: item %d has no question", i+1)
+		}
+		switch item.Type {
+		case surveySingle, surveyMulti:
+			if len(item.Options) == 0 {
+				return fmt.Errorf("survey: item %d has no options", i+1)
+			}
+		case surveyScale:
+			if item.Min < -surveyMaxScaleValue || item.Max > surveyMaxScaleValue || item.Min >= item.Max || item.Max-item.Min+1 > surveyMaxScaleSteps {
+				return fmt.Errorf("survey: item %d has an invalid scale (%d-%d)", i+1, item.Min, item.Max)
+			}
+		case surveyNumber, surveyText:
+			item.Options = nil
+		default:
+			return fmt.Errorf("survey: item %d has unknown type '%s'", i+1, item.Type)
+		}
+	}
+	s.title = config.Title
+	s.items = config.Items
+	s.index = make(map[string]int)
+
+	go func() {
+		s.userHTML <- s.GetLastHTMLUser()
+	}()
+	go func() {
+		s.adminHTML <- s.GetLastHTMLAdmin()
+	}()
+
+	s.ctx = context.Background()
+	s.ctx, s.cancel = context.WithCancel(s.ctx)
+	go func() {
+		done := s.ctx.Done()
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case b := <-s.adminInput:
+				s.l.Lock()
+				if string(b) == "close" && !s.finished {
+					s.finished = true
+					s.l.Unlock()
+					s.adminHTML <- s.GetLastHTMLAdmin()
+					s.userHTML <- s.GetLastHTMLUser()
+				} else {
+					s.l.Unlock()
+				}
+
+			case b := <-s.userInput:
+				s.receive(registry.ParticipantInput{Data: b})
+
+			case p := <-s.participantInput:
+				s.receive(p)
+
+			case <-ticker.C:
+				s.l.Lock()
+				changed := s.changed && !s.finished
+				s.changed = false
+				s.l.Unlock()
+
+				if changed {
+					s.adminHTML <- s.GetLastHTMLAdmin()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// parseAnswer validates a single answer for an item. It returns nil for missing answers.
+func (item surveyItem) parseAnswer(b json.RawMessage) (interface{}, error) {
+	if len(b) == 0 || string(b) == "null" {
+		return nil, nil
+	}
+
+	switch item.Type {
+	case surveySingle:
+		var i int
+		err := json.Unmarshal(b, &i)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= len(item.Options) {
+			return nil, fmt.Errorf("option %d out of range", i)
+		}
+		return i, nil
+	case surveyMulti:
+		var l []int
+		err := json.Unmarshal(b, &l)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[int]bool)
+		for _, i := range l {
+			if i < 0 || i >= len(item.Options) || seen[i] {
+				return nil, fmt.Errorf("invalid option %d", i)
+			}
+			seen[i] = true
+		}
+		if len(l) == 0 {
+			return nil, nil
+		}
+		return l, nil
+	case surveyNumber:
+		var f float64
+		err := json.Unmarshal(b, &f)
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, errors.New("invalid number")
+		}
+		return f, nil
+	case surveyText:
+		var t string
+		err := json.Unmarshal(b, &t)
+		if err != nil {
+			return nil, err
+		}
+		t = strings.TrimSpace(t)
+		if t == "" {
+			return nil, nil
+		}
+		if len([]rune(t)) > surveyMaxTextLength {
+			t = string([]rune(t)[:surveyMaxTextLength])
+		}
+		return t, nil
+	case surveyScale:
+		var i int
+		err := json.Unmarshal(b, &i)
+		if err != nil {
+			return nil, err
+		}
+		if i < item.Min || i > item.Max {
+			return nil, fmt.Errorf("value %d out of range", i)
+		}
+		return i, nil
+	}
+	return nil, fmt.Errorf("unknown type '%s'", item.Type)
+}
+
+// format returns a human readable representation of an answer.
+func (item surveyItem) format(a interface{}) string {
+	switch v := a.(type) {
+	case nil:
+		return ""
+	case int:
+		if item.Type == surveySingle {
+			return item.Options[v]
+		}
+		return strconv.Itoa(v)
+	case []int:
+		s := make([]string, len(v))
+		for i := range v {
+			s[i] = item.Options[v[i]]
+		}
+		return strings.Join(s, "; ")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	}
+	return fmt.Sprint(a)
+}
+
+// receive handles the answers of a participant. A new submission of the same participant replaces the old one.
+func (s *survey) receive(p registry.ParticipantInput) {
+	var a surveyAnswer
+	err := json.Unmarshal(p.Data, &a)
+	if err != nil || len(a.Answers) != len(s.items) {
+		return
+	}
+
+	r := make(surveyResponse, len(s.items))
+	for i := range s.items {
+		r[i], err = s.items[i].parseAnswer(a.Answers[i])
+		if err != nil {
+			return
+		}
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.finished {
+		return
+	}
+	if i, ok := s.index[p.Participant]; ok && p.Participant != "" {
+		s.responses[i] = r
+	} else {
+		s.index[p.Participant] = len(s.responses)
+		s.responses = append(s.responses, r)
+	}
+	s.changed = true
+}
+
+func (s *survey) GetLastHTMLUser() template.HTML {
+	s.l.Lock()
+	finished := s.finished
+	s.l.Unlock()
+
+	td := surveyUserStruct{
+		Title:       s.title,
+		Items:       s.items,
+		MaxText:     surveyMaxTextLength,
+		Finished:    finished,
+		Translation: translation.GetDefaultTranslation(),
+	}
+
+	var buf bytes.Buffer
+	err := surveyUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing survey user: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+// table returns all responses in human readable form. Caller must hold l.
+func (s *survey) table() [][]string {
+	t := make([][]string, len(s.responses))
+	for i := range s.responses {
+		t[i] = make([]string, len(s.items))
+		for j := range s.items {
+			t[i][j] = s.items[j].format(s.responses[i][j])
+		}
+	}
+	return t
+}
+
+func (s *survey) IsClosed() bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.finished
+}
+
+func (s *survey) GetLastHTMLAdmin() template.HTML {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	td := surveyAdminStruct{
+		Title:       s.title,
+		Items:       s.items,
+		Summaries:   make([]surveySummary, len(s.items)),
+		Table:       s.table(),
+		Finished:    s.finished,
+		Translation: translation.GetDefaultTranslation(),
+	}
+
+	for i, item := range s.items {
+		td.Summaries[i].Question = item.Question
+		switch item.Type {
+		case surveySingle, surveyMulti:
+			v := make([]helper.ChartValue, len(item.Options))
+			for j := range item.Options {
+				v[j].Label = item.Options[j]
+			}
+			for _, r := range s.responses {
+				switch a := r[i].(type) {
+				case int:
+					v[a].Value++
+				case []int:
+					for _, o := range a {
+						v[o].Value++
+					}
+				}
+			}
+			td.Summaries[i].Chart = helper.BarChart(v, fmt.Sprintf("Survey_chart_%d", i), item.Question)
+		case surveyScale, surveyNumber:
+			values := make([]float64, 0, len(s.responses))
+			for _, r := range s.responses {
+				switch a := r[i].(type) {
+				case int:
+					values = append(values, float64(a))
+				case float64:
+					values = append(values, a)
+				}
+			}
+			if len(values) > 0 {
+				stat := helper.CalculateStatistics(values)
+				td.Summaries[i].Statistics = &surveyStatistics{N: stat.N, Mean: helper.FormatFloat(stat.Mean), Median: helper.FormatFloat(stat.Median)}
+			}
+			if item.Type == surveyScale {
+				v := make([]helper.ChartValue, item.Max-item.Min+1)
+				for j := range v {
+					v[j].Label = strconv.Itoa(item.Min + j)
+				}
+				for _, f := range values {
+					v[int(f)-item.Min].Value++
+				}
+				td.Summaries[i].Chart = helper.BarChart(v, fmt.Sprintf("Survey_chart_%d", i), item.Question)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	err := surveyAdminTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing survey admin: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
+
+func (s *survey) Deactivate() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+func (s *survey) GetAdminDownload() []byte {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := make([]string, 0, len(s.items)+1)
+	header = append(header, "participant")
+	for i := range s.items {
+		header = append(header, s.items[i].Question)
+	}
+	w.Write(header)
+	for i, r := range s.table() {
+		w.Write(append([]string{strconv.Itoa(i + 1)}, r...))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return []byte(err.Error())
+	}
+	return buf.Bytes()
+}
@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020,2023,2025 Marcus Soll
+// Copyright 2020,2023,2025,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -22,8 +22,10 @@ import (
 	"fmt"
 	"html/template"
 	"log"
+	"math"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -39,29 +41,111 @@ func init() {
 	}
 }
 
+const (
+	timeQuestionTime          = "time"
+	timeQuestionDateTime      = "datetime"
+	timeQuestionRange         = "range"
+	timeQuestionDateTimeRange = "datetimerange"
+)
+
+const (
+	timeQuestionTimeLayout     = "15:04"
+	timeQuestionDateTimeLayout = "2006-01-02T15:04"
+	timeQuestionRangeSeperator = "/"
+	timeQuestionMaxBuckets     = 10000 // maximum number of buckets a single range may cover
+	timeQuestionDefaultWindow  = 30    // minutes
+)
+
+// timeQuestionDay is the day all answers of time of day questions are placed on.
+var timeQuestionDay = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
 const timeQuestionConfig = `
 <h1>%s</h1>
 <p>%s: <input id="TimeQuestion" type="text"></p>
+<p>%s: <select id="TimeQuestionMode">
+<option value="time">%s</option>
+<option value="datetime">%s</option>
+<option value="range">%s</option>
+<option value="datetimerange">%s</option>
+</select></p>
 <p>%s: <input id="TimeQuestionPrecision" type="number" min="1" max="60" value="1"> %s</p>
-<p><button onclick="sendActivate('TimeQuestion', JSON.stringify({'q': document.getElementById('TimeQuestion').value, 'p': document.getElementById('TimeQuestionPrecision').value}))">%s</button></p>
-<p><button onclick="saveElement('TimeQuestion', JSON.stringify({'q': document.getElementById('TimeQuestion').value, 'p': document.getElementById('TimeQuestionPrecision').value}), '%s: '+document.getElementById('TimeQuestion').value)">%s</button></p>
+<p>%s: <input id="TimeQuestionWindow" type="number" min="1" value="30"> %s</p>
+<p>%s: <input id="TimeQuestionTimeLimit" type="number" min="0"> %s</p>
+<p><button onclick="sendActivate('TimeQuestion', timeQuestionGetData())">%s</button></p>
+<p><button onclick="saveElement('TimeQuestion', timeQuestionGetData(), '%s: '+document.getElementById('TimeQuestion').value)">%s</button></p>
+
+<script>
+function timeQuestionGetData() {
+	return JSON.stringify({'q': document.getElementById('TimeQuestion').value, 'm': document.getElementById('TimeQuestionMode').value, 'p': document.getElementById('TimeQuestionPrecision').value, 'w': document.getElementById('TimeQuestionWindow').value, 'tl': document.getElementById('TimeQuestionTimeLimit').value});
+}
+</script>
 `
 
 const timeQuestionUser = `
 <h1>{{.Question}}</h1>
-{{$.Translation.DisplayTimeQuestion}}: <input id="timeQuestionInput" type="time" onchange="document.getElementById('timeQuestionButton').disabled = document.getElementById('timeQuestionInput').value == ''">
-<button id="timeQuestionButton" onclick="if(!document.getElementById('timeQuestionInput').reportValidity()){return;};sendData('TimeQuestion',document.getElementById('timeQuestionInput').value);document.getElementById('timeQuestionInput').disabled=true;document.getElementById('timeQuestionButton').disabled=true;" disabled>{{$.Translation.Submit}}</button>
+{{.TimeLimit}}
+{{if .Range}}
+{{$.Translation.TimeFrom}}: <input id="timeQuestionInput" type="{{.InputType}}" onchange="timeQuestionCheck()">
+{{$.Translation.TimeTo}}: <input id="timeQuestionInputTo" type="{{.InputType}}" onchange="timeQuestionCheck()">
+{{else}}
+{{$.Translation.DisplayTimeQuestion}}: <input id="timeQuestionInput" type="{{.InputType}}" onchange="timeQuestionCheck()">
+{{end}}
+<button id="timeQuestionButton" onclick="timeQuestionSend()" disabled>{{$.Translation.Submit}}</button>
+
+<script>
+function timeQuestionCheck() {
+	let to = document.getElementById('timeQuestionInputTo');
+	document.getElementById('timeQuestionButton').disabled = document.getElementById('timeQuestionInput').value == '' || (to !== null && to.value == '');
+}
+
+function timeQuestionSend() {
+	let from = document.getElementById('timeQuestionInput');
+	let to = document.getElementById('timeQuestionInputTo');
+	if(!from.reportValidity() || (to !== null && !to.reportValidity())) {
+		return;
+	}
+	let value = from.value;
+	if(to !== null) {
+		value += '{{.Seperator}}' + to.value;
+		to.disabled = true;
+	}
+	sendData('TimeQuestion', value);
+	from.disabled = true;
+	document.getElementById('timeQuestionButton').disabled = true;
+}
+</script>
 `
 
 var timeQuestionUserTemplate = template.Must(template.New("timeQuestionUser").Parse(timeQuestionUser))
 
 type timeQuestionUserStruct struct {
 	Question    string
+	TimeLimit   template.HTML
+	Range       bool
+	InputType   string
+	Seperator   string
 	Translation translation.Translation
 }
 
+const timeQuestionStatistics = `
+{{define "statistics"}}
+{{if .Statistics.Mean}}
+<p>{{.Translation.Mean}}: <strong>{{.Statistics.Mean}}</strong>{{if .Statistics.Agreement}} ({{.Translation.Agreement}}: {{.Statistics.Agreement}} %){{end}}</p>
+{{end}}
+{{if .Statistics.Median}}
+<p>{{.Translation.Median}}: <strong>{{.Statistics.Median}}</strong></p>
+{{end}}
+{{if .Statistics.IntervalCount}}
+<p>{{.Translation.MostAgreedInterval}}: <strong>{{.Statistics.IntervalStart}} - {{.Statistics.IntervalEnd}}</strong> ({{.Statistics.IntervalCount}})</p>
+{{end}}
+{{end}}
+`
+
 const timeQuestionAdmin = `
 <h1>{{.Question}}</h1>
+{{.TimeLimit}}
+{{template "statistics" .}}
+{{.Chart}}
 <table style="border: none;">
 {{range $i, $e := .Answers}}
     <tr style="border: none;">
@@ -77,7 +161,7 @@ const timeQuestionAdmin = `
 <p><button onclick="sendData('TimeQuestion', 'close')">{{.Translation.Finish}}</button></p>
 `
 
-var timeQuestionAdminTemplate = template.Must(template.New("timeQuestionAdmin").Parse(timeQuestionAdmin))
+var timeQuestionAdminTemplate = template.Must(template.New("timeQuestionAdmin").Parse(timeQuestionAdmin + timeQuestionStatistics))
 
 type timeQuestionAdminStruct struct {
 	Question string
@@ -86,9 +170,42 @@ type timeQuestionAdminStruct struct {
 		Count    int
 	}
 	Submitted   int
+	Statistics  timeQuestionStatisticsStruct
+	Chart       template.HTML
+	TimeLimit   template.HTML
 	Translation translation.Translation
 }
 
+const timeQuestionResult = `
+<h1>{{.Question}}</h1>
+{{template "statistics" .}}
+{{.Chart}}
+`
+
+var timeQuestionResultTemplate = template.Must(template.New("timeQuestionResult").Parse(timeQuestionResult + timeQuestionStatistics))
+
+type timeQuestionResultStruct struct {
+	Question    string
+	Statistics  timeQuestionStatisticsStruct
+	Chart       template.HTML
+	Translation translation.Translation
+}
+
+type timeQuestionStatisticsStruct struct {
+	Mean          string
+	Median        string
+	Agreement     string
+	IntervalStart string
+	IntervalEnd   string
+	IntervalCount int
+}
+
+// timeQuestionAnswer represents a single answer. For answers without a range, Start and End are equal.
+type timeQuestionAnswer struct {
+	Start time.Time
+	End   time.Time
+}
+
 type timeQuestion struct {
 	adminHTML  chan<- template.HTML
 	userHTML   chan<- template.HTML
@@ -98,17 +215,19 @@ type timeQuestion struct {
 	cancel     context.CancelFunc
 
 	Question            string
+	Mode                string
 	Precision           time.Duration
-	TimeQuestionAnswers map[time.Time]int
-	NumberSubmitted     int
+	Window              time.Duration
+	TimeQuestionAnswers []timeQuestionAnswer
 	TimeQuestionChanged bool
 	AnswerLock          sync.Mutex
 	Finished            bool
+	limit               timeLimit
 }
 
 func (n *timeQuestion) ConfigHTML() (string, template.HTML) {
 	tl := translation.GetDefaultTranslation()
-	return tl.DisplayTimeQuestion, template.HTML(fmt.Sprintf(timeQuestionConfig, template.HTMLEscapeString(tl.DisplayTimeQuestion), template.HTMLEscapeString(tl.DisplayQuestion), template.HTMLEscapeString(tl.Precision), template.HTMLEscapeString(tl.Minutes), template.HTMLEscapeString(tl.Activate), template.HTMLEscapeString(tl.DisplayTimeQuestion), template.HTMLEscapeString(tl.SaveElement)))
+	return tl.DisplayTimeQuestion, template.HTML(fmt.Sprintf(timeQuestionConfig, template.HTMLEscapeString(tl.DisplayTimeQuestion), template.HTMLEscapeString(tl.DisplayQuestion), template.HTMLEscapeString(tl.TimeQuestionMode), template.HTMLEscapeString(tl.TimeOfDay), template.HTMLEscapeString(tl.DateAndTime), template.HTMLEscapeString(tl.TimeRange), template.HTMLEscapeString(tl.DateAndTimeRange), template.HTMLEscapeString(tl.Precision), template.HTMLEscapeString(tl.Minutes), template.HTMLEscapeString(tl.IntervalLength), template.HTMLEscapeString(tl.Minutes), template.HTMLEscapeString(tl.TimeLimit), template.HTMLEscapeString(tl.Seconds), template.HTMLEscapeString(tl.Activate), template.HTMLEscapeString(tl.DisplayTimeQuestion), template.HTMLEscapeString(tl.SaveElement)))
 }
 
 func (n *timeQuestion) AdminHTMLChannel(c chan<- template.HTML) {
@@ -139,6 +258,15 @@ func (n *timeQuestion) Activate(b []byte) error {
 		return fmt.Errorf("no question found")
 	}
 
+	n.Mode = input["m"]
+	switch n.Mode {
+	case "":
+		n.Mode = timeQuestionTime
+	case timeQuestionTime, timeQuestionDateTime, timeQuestionRange, timeQuestionDateTimeRange:
+	default:
+		return fmt.Errorf("unknown mode '%s'", n.Mode)
+	}
+
 	precision := input["p"]
 	if precision == "" {
 		return fmt.Errorf("no precision found")
@@ -148,22 +276,34 @@ func (n *timeQuestion) Activate(b []byte) error {
 	if err != nil {
 		return fmt.Errorf("can not parse precision: %w", err)
 	}
+	if p <= 0 {
+		return fmt.Errorf("precision must be positive")
+	}
 
 	n.Precision = time.Duration(p) * time.Minute
 
-	n.TimeQuestionAnswers = make(map[time.Time]int)
-
-	go func() {
-		td := timeQuestionUserStruct{
-			Question:    n.Question,
-			Translation: translation.GetDefaultTranslation(),
-		}
-		var buf bytes.Buffer
-		err := timeQuestionUserTemplate.Execute(&buf, td)
+	w := timeQuestionDefaultWindow
+	if input["w"] != "" {
+		w, err = strconv.Atoi(input["w"])
 		if err != nil {
-			log.Printf("error executing timeQuestionUser: %s", err.Error())
+			return fmt.Errorf("can not parse interval length: %w", err)
 		}
-		n.userHTML <- template.HTML(buf.Bytes())
+		if w <= 0 {
+			return fmt.Errorf("interval length must be positive")
+		}
+	}
+	n.Window = time.Duration(w) * time.Minute
+
+	n.TimeQuestionAnswers = make([]timeQuestionAnswer, 0)
+
+	d, err := parseTimeLimit(input)
+	if err != nil {
+		return err
+	}
+	timeout := n.limit.Start(d)
+
+	go func() {
+		n.userHTML <- n.getUserPage()
 	}()
 	go func() {
 		n.adminHTML <- n.getAdminPage()
@@ -178,25 +318,21 @@ func (n *timeQuestion) Activate(b []byte) error {
 		for {
 			select {
 			case b := <-n.adminInput:
-				n.AnswerLock.Lock()
-				if string(b) == "close" && !n.Finished {
-					n.Finished = true
-					n.AnswerLock.Unlock()
-					t := n.timeQuestionGetChart()
-					n.adminHTML <- t
-					n.userHTML <- t
-				} else {
-					n.AnswerLock.Unlock()
+				if string(b) == "close" {
+					n.finish()
 				}
 
+			case <-timeout:
+				n.finish()
+
 			case b := <-n.userInput:
-				t, err := time.Parse("15:04", string(b))
+				a, err := n.parseAnswer(string(b))
 				if err == nil {
-					t = t.Truncate(n.Precision)
 					n.AnswerLock.Lock()
-					n.TimeQuestionAnswers[t]++
-					n.NumberSubmitted++
-					n.TimeQuestionChanged = true
+					if !n.Finished {
+						n.TimeQuestionAnswers = append(n.TimeQuestionAnswers, a)
+						n.TimeQuestionChanged = true
+					}
 					n.AnswerLock.Unlock()
 				}
 
@@ -218,29 +354,332 @@ func (n *timeQuestion) Activate(b []byte) error {
 	return nil
 }
 
-func (n *timeQuestion) GetLastHTMLUser() template.HTML {
+// isTimeOfDay returns whether answers only contain the time of the day (and thus are circular).
+func (n *timeQuestion) isTimeOfDay() bool {
+	return n.Mode == timeQuestionTime || n.Mode == timeQuestionRange
+}
 
-	if n.Finished {
-		return n.timeQuestionGetChart()
+// isRange returns whether answers are ranges.
+func (n *timeQuestion) isRange() bool {
+	return n.Mode == timeQuestionRange || n.Mode == timeQuestionDateTimeRange
+}
+
+// parseTime parses a single time according to the mode of the question.
+func (n *timeQuestion) parseTime(s string) (time.Time, error) {
+	if n.isTimeOfDay() {
+		t, err := time.Parse(timeQuestionTimeLayout, s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return timeQuestionDay.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute), nil
 	}
+	return time.Parse(timeQuestionDateTimeLayout, s)
+}
 
-	n.AnswerLock.Lock()
-	defer n.AnswerLock.Unlock()
+// parseAnswer parses the input of a participant.
+// Ranges are given as "start/end". Time of day ranges ending before they start span midnight.
+func (n *timeQuestion) parseAnswer(s string) (timeQuestionAnswer, error) {
+	if !n.isRange() {
+		t, err := n.parseTime(s)
+		return timeQuestionAnswer{Start: t, End: t}, err
+	}
 
-	td := timeQuestionUserStruct{
-		Question:    n.Question,
-		Translation: translation.GetDefaultTranslation(),
+	split := strings.Split(s, timeQuestionRangeSeperator)
+	if len(split) != 2 {
+		return timeQuestionAnswer{}, fmt.Errorf("invalid range '%s'", s)
 	}
-	var buf bytes.Buffer
-	err := timeQuestionUserTemplate.Execute(&buf, td)
+	start, err := n.parseTime(split[0])
 	if err != nil {
-		log.Printf("error executing timeQuestionUser: %s", err.Error())
+		return timeQuestionAnswer{}, err
 	}
-	return template.HTML(buf.Bytes())
+	end, err := n.parseTime(split[1])
+	if err != nil {
+		return timeQuestionAnswer{}, err
+	}
+	if n.isTimeOfDay() && !end.After(start) {
+		end = end.Add(24 * time.Hour)
+	}
+	if !end.After(start) {
+		return timeQuestionAnswer{}, fmt.Errorf("range ends before it starts")
+	}
+	if end.Sub(start)/n.Precision > timeQuestionMaxBuckets {
+		return timeQuestionAnswer{}, fmt.Errorf("range too long")
+	}
+	return timeQuestionAnswer{Start: start, End: end}, nil
+}
+
+// format returns the representation of a time suitable for the mode of the question.
+func (n *timeQuestion) format(t time.Time) string {
+	if n.isTimeOfDay() {
+		return t.Format(timeQuestionTimeLayout)
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// wrap moves a time of day back into timeQuestionDay. All other times are returned unchanged.
+func (n *timeQuestion) wrap(t time.Time) time.Time {
+	if !n.isTimeOfDay() {
+		return t
+	}
+	for !t.Before(timeQuestionDay.Add(24 * time.Hour)) {
+		t = t.Add(-24 * time.Hour)
+	}
+	return t
+}
+
+// buckets returns the number of answers per bucket of length Precision, sorted by time.
+// Ranges are counted in every bucket they overlap with. Caller must hold AnswerLock.
+func (n *timeQuestion) buckets() []helper.TimeValue {
+	count := make(map[time.Time]int)
+	for _, a := range n.TimeQuestionAnswers {
+		start := a.Start.Truncate(n.Precision)
+		if !n.isRange() {
+			count[start]++
+			continue
+		}
+		for b := start; b.Before(a.End); b = b.Add(n.Precision) {
+			count[n.wrap(b)]++
+		}
+	}
+
+	result := make([]helper.TimeValue, 0, len(count))
+	for t, c := range count {
+		result = append(result, helper.TimeValue{Time: t, Value: float64(c)})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+	return result
+}
+
+// statistics calculates mean, median and the most agreed interval of all answers. Caller must hold AnswerLock.
+// For times of the day, circular statistics are used so that e.g. 23:50 and 00:10 have a mean of 00:00.
+// For ranges, the most agreed interval is the longest interval in which most ranges overlap.
+func (n *timeQuestion) statistics() timeQuestionStatisticsStruct {
+	var s timeQuestionStatisticsStruct
+	if len(n.TimeQuestionAnswers) == 0 {
+		return s
+	}
+
+	if n.isRange() {
+		start, end, count := timeQuestionMaxOverlap(n.TimeQuestionAnswers, n.isTimeOfDay())
+		s.IntervalStart = n.format(start)
+		s.IntervalEnd = n.format(n.wrap(end))
+		s.IntervalCount = count
+		return s
+	}
+
+	times := make([]time.Time, len(n.TimeQuestionAnswers))
+	for i := range n.TimeQuestionAnswers {
+		times[i] = n.TimeQuestionAnswers[i].Start
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	if n.isTimeOfDay() {
+		minutes := make([]float64, len(times))
+		for i := range times {
+			minutes[i] = times[i].Sub(timeQuestionDay).Minutes()
+		}
+		mean, r := circularMean(minutes, 24*60)
+		if r > 1e-9 {
+			s.Mean = n.format(timeQuestionDay.Add(time.Duration(math.Round(mean)) * time.Minute))
+			s.Agreement = helper.FormatFloat(r * 100)
+		}
+		s.Median = n.format(timeQuestionDay.Add(time.Duration(math.Round(circularMedian(minutes, 24*60))) * time.Minute))
+	} else {
+		var sum float64
+		for i := range times {
+			sum += float64(times[i].Sub(times[0]))
+		}
+		s.Mean = n.format(times[0].Add(time.Duration(sum / float64(len(times)))).Round(time.Minute))
+		median := times[len(times)/2]
+		if len(times)%2 == 0 {
+			median = times[len(times)/2-1].Add(times[len(times)/2].Sub(times[len(times)/2-1]) / 2)
+		}
+		s.Median = n.format(median.Round(time.Minute))
+	}
+
+	// Most agreed interval: window of length Window containing the most answers.
+	period := time.Duration(0)
+	if n.isTimeOfDay() {
+		period = 24 * time.Hour
+	}
+	start, end, count := timeQuestionDensestWindow(times, n.Window, period)
+	s.IntervalStart = n.format(start)
+	s.IntervalEnd = n.format(n.wrap(end))
+	s.IntervalCount = count
+	return s
+}
+
+// circularMean returns the circular mean of the values with the given period and the mean resultant length (between 0 and 1).
+// A mean resultant length near 0 means that the values are evenly spread and the mean is meaningless.
+func circularMean(v []float64, period float64) (float64, float64) {
+	var sin, cos float64
+	for i := range v {
+		a := v[i] / period * 2 * math.Pi
+		sin += math.Sin(a)
+		cos += math.Cos(a)
+	}
+	sin /= float64(len(v))
+	cos /= float64(len(v))
+	mean := math.Atan2(sin, cos) / (2 * math.Pi) * period
+	if mean < 0 {
+		mean += period
+	}
+	return mean, math.Hypot(sin, cos)
+}
+
+// circularMedian returns the value minimising the sum of circular distances to all values.
+// The values are sorted once, afterwards all candidates are checked in a single pass using prefix sums.
+func circularMedian(v []float64, period float64) float64 {
+	n := len(v)
+	a := make([]float64, 2*n)
+	for i := range v {
+		a[i] = math.Mod(v[i], period)
+		if a[i] < 0 {
+			a[i] += period
+		}
+	}
+	sort.Float64s(a[:n])
+	for i := 0; i < n; i++ {
+		a[n+i] = a[i] + period
+	}
+	prefix := make([]float64, 2*n+1)
+	for i := range a {
+		prefix[i+1] = prefix[i] + a[i]
+	}
+
+	best := a[0]
+	bestSum := math.Inf(1)
+	k := 0
+	for i := 0; i < n; i++ {
+		c := a[i]
+		// Values a[i:k] are at most half a period ahead of c, a[k:i+n] are closer going backwards.
+		if k < i {
+			k = i
+		}
+		for k < i+n && a[k]-c <= period/2 {
+			k++
+		}
+		sum := prefix[k] - prefix[i] - float64(k-i)*c
+		sum += float64(i+n-k)*(period+c) - (prefix[i+n] - prefix[k])
+		if sum < bestSum {
+			best = c
+			bestSum = sum
+		}
+	}
+	return best
+}
+
+// timeQuestionDensestWindow returns the window of length width containing the most of the sorted times.
+// The returned interval spans from the first to the last time inside the window.
+// If period is not 0, times are treated as circular with the given period.
+func timeQuestionDensestWindow(times []time.Time, width time.Duration, period time.Duration) (time.Time, time.Time, int) {
+	extended := times
+	if period != 0 {
+		extended = make([]time.Time, 0, 2*len(times))
+		extended = append(extended, times...)
+		for i := range times {
+			extended = append(extended, times[i].Add(period))
+		}
+	}
+
+	bestStart, bestEnd, bestCount := 0, 0, 0
+	j := 0
+	for i := range times {
+		if j < i {
+			j = i
+		}
+		for j < len(extended) && j-i < len(times) && extended[j].Sub(extended[i]) < width {
+			j++
+		}
+		if j-i > bestCount {
+			bestStart, bestEnd, bestCount = i, j-1, j-i
+		}
+	}
+	return extended[bestStart], extended[bestEnd], bestCount
+}
+
+// timeQuestionMaxOverlap returns the first longest interval covered by most answers.
+// If timeOfDay is true, ranges spanning midnight are split.
+func timeQuestionMaxOverlap(answers []timeQuestionAnswer, timeOfDay bool) (time.Time, time.Time, int) {
+	type event struct {
+		t     time.Time
+		delta int
+	}
+	events := make([]event, 0, 2*len(answers))
+	endOfDay := timeQuestionDay.Add(24 * time.Hour)
+	for _, a := range answers {
+		if timeOfDay && a.End.After(endOfDay) {
+			events = append(events, event{a.Start, 1}, event{endOfDay, -1})
+			events = append(events, event{timeQuestionDay, 1}, event{a.End.Add(-24 * time.Hour), -1})
+			continue
+		}
+		events = append(events, event{a.Start, 1}, event{a.End, -1})
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].t.Before(events[j].t) })
+
+	// Count overlapping answers between consecutive event times
+	type segment struct {
+		start, end time.Time
+		count      int
+	}
+	segments := make([]segment, 0, len(events))
+	current, best := 0, 0
+	for i := 0; i < len(events); {
+		t := events[i].t
+		for i < len(events) && events[i].t.Equal(t) {
+			current += events[i].delta
+			i++
+		}
+		if i < len(events) && current > 0 {
+			segments = append(segments, segment{t, events[i].t, current})
+			if current > best {
+				best = current
+			}
+		}
+	}
+
+	// Find the longest run of adjacent segments with the highest count
+	var bestStart, bestEnd time.Time
+	for i := 0; i < len(segments); i++ {
+		if segments[i].count != best {
+			continue
+		}
+		start, end := segments[i].start, segments[i].end
+		for i+1 < len(segments) && segments[i+1].count == best && segments[i+1].start.Equal(end) {
+			i++
+			end = segments[i].end
+		}
+		if end.Sub(start) > bestEnd.Sub(bestStart) {
+			bestStart, bestEnd = start, end
+		}
+	}
+	return bestStart, bestEnd, best
+}
+
+func (n *timeQuestion) GetLastHTMLUser() template.HTML {
+	n.AnswerLock.Lock()
+	finished := n.Finished
+	n.AnswerLock.Unlock()
+
+	if finished {
+		return n.timeQuestionGetChart()
+	}
+
+	return n.getUserPage()
+}
+
+func (n *timeQuestion) IsClosed() bool {
+	n.AnswerLock.Lock()
+	defer n.AnswerLock.Unlock()
+	return n.Finished
 }
 
 func (n *timeQuestion) GetLastHTMLAdmin() template.HTML {
-	if n.Finished {
+	n.AnswerLock.Lock()
+	finished := n.Finished
+	n.AnswerLock.Unlock()
+
+	if finished {
 		return n.timeQuestionGetChart()
 	}
 	return n.getAdminPage()
@@ -250,53 +689,91 @@ func (n *timeQuestion) Deactivate() {
 	if n.cancel != nil {
 		n.cancel()
 	}
+	n.limit.Stop()
 }
 
-func (n *timeQuestion) timeQuestionGetChart() template.HTML {
+// finish closes the question and sends the results to everyone.
+// It does nothing if the question is already closed.
+func (n *timeQuestion) finish() {
+	n.AnswerLock.Lock()
+	if n.Finished {
+		n.AnswerLock.Unlock()
+		return
+	}
+	n.Finished = true
+	n.AnswerLock.Unlock()
+
+	n.limit.Stop()
+	t := n.timeQuestionGetChart()
+	n.adminHTML <- t
+	n.userHTML <- t
+}
+
+func (n *timeQuestion) getUserPage() template.HTML {
 	n.AnswerLock.Lock()
 	defer n.AnswerLock.Unlock()
 
-	keys := make([]time.Time, 0, len(n.TimeQuestionAnswers))
-	for k := range n.TimeQuestionAnswers {
-		keys = append(keys, k)
+	td := timeQuestionUserStruct{
+		Question:    n.Question,
+		TimeLimit:   n.limit.HTML(),
+		Range:       n.isRange(),
+		InputType:   "time",
+		Seperator:   timeQuestionRangeSeperator,
+		Translation: translation.GetDefaultTranslation(),
+	}
+	if !n.isTimeOfDay() {
+		td.InputType = "datetime-local"
+	}
+	var buf bytes.Buffer
+	err := timeQuestionUserTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing timeQuestionUser: %s", err.Error())
 	}
+	return template.HTML(buf.Bytes())
+}
 
-	sort.Slice(keys, func(i, j int) bool { return keys[i].Before(keys[j]) })
+func (n *timeQuestion) timeQuestionGetChart() template.HTML {
+	n.AnswerLock.Lock()
+	defer n.AnswerLock.Unlock()
 
-	v := make([]helper.ChartValue, len(keys))
+	td := timeQuestionResultStruct{
+		Question:    n.Question,
+		Statistics:  n.statistics(),
+		Chart:       helper.TimeHistogram(n.buckets(), n.Precision, "timeQuestionChart", n.Question, n.isTimeOfDay()),
+		Translation: translation.GetDefaultTranslation(),
+	}
 
-	for i := range keys {
-		v[i].Label = fmt.Sprintf("%02d:%02d", keys[i].Hour(), keys[i].Minute())
-		v[i].Value = float64(n.TimeQuestionAnswers[keys[i]])
+	var buf bytes.Buffer
+	err := timeQuestionResultTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing timeQuestionResult: %s", err.Error())
 	}
-	return helper.BarChart(v, "timeQuestionChart", n.Question)
+	return template.HTML(buf.Bytes())
 }
 
 func (n *timeQuestion) getAdminPage() template.HTML {
 	n.AnswerLock.Lock()
 	defer n.AnswerLock.Unlock()
 
-	keys := make([]time.Time, 0, len(n.TimeQuestionAnswers))
-	for k := range n.TimeQuestionAnswers {
-		keys = append(keys, k)
-	}
-
-	sort.Slice(keys, func(i, j int) bool { return keys[i].Before(keys[j]) })
+	buckets := n.buckets()
 
 	td := timeQuestionAdminStruct{
 		Question: n.Question,
 		Answers: make([]struct {
 			Question string
 			Count    int
-		}, 0, len(n.TimeQuestionAnswers)),
-		Submitted:   n.NumberSubmitted,
+		}, 0, len(buckets)),
+		Submitted:   len(n.TimeQuestionAnswers),
+		Statistics:  n.statistics(),
+		Chart:       helper.TimeHistogram(buckets, n.Precision, "timeQuestionChart", n.Question, n.isTimeOfDay()),
+		TimeLimit:   n.limit.HTML(),
 		Translation: translation.GetDefaultTranslation(),
 	}
-	for i := range keys {
+	for i := range buckets {
 		td.Answers = append(td.Answers, struct {
 			Question string
 			Count    int
-		}{fmt.Sprintf("%02d:%02d", keys[i].Hour(), keys[i].Minute()), n.TimeQuestionAnswers[keys[i]]})
+		}{n.format(buckets[i].Time), int(buckets[i].Value)})
 	}
 
 	var buf bytes.Buffer
@@ -307,17 +784,41 @@ func (n *timeQuestion) getAdminPage() template.HTML {
 	return template.HTML(buf.Bytes())
 }
 
+type timeQuestionDownloadAnswer struct {
+	Start string
+	End   string `json:",omitempty"`
+}
+
+type timeQuestionDownload struct {
+	Question   string
+	Mode       string
+	Answers    []timeQuestionDownloadAnswer
+	Buckets    map[string]int
+	Statistics timeQuestionStatisticsStruct
+}
+
 func (n *timeQuestion) GetAdminDownload() []byte {
 	n.AnswerLock.Lock()
 	defer n.AnswerLock.Unlock()
 
-	r := make(map[string]int, len(n.TimeQuestionAnswers))
-
-	for t := range n.TimeQuestionAnswers {
-		r[t.Format("15:04")] = n.TimeQuestionAnswers[t]
+	d := timeQuestionDownload{
+		Question:   n.Question,
+		Mode:       n.Mode,
+		Answers:    make([]timeQuestionDownloadAnswer, len(n.TimeQuestionAnswers)),
+		Buckets:    make(map[string]int),
+		Statistics: n.statistics(),
+	}
+	for i, a := range n.TimeQuestionAnswers {
+		d.Answers[i].Start = n.format(a.Start)
+		if n.isRange() {
+			d.Answers[i].End = n.format(n.wrap(a.End))
+		}
+	}
+	for _, b := range n.buckets() {
+		d.Buckets[n.format(b.Time)] = int(b.Value)
 	}
 
-	b, err := json.Marshal(r)
+	b, err := json.Marshal(d)
 	if err != nil {
 		return []byte(err.Error())
 	}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/responsego/translation"
+)
+
+// timeLimitKey is the key of the optional time limit (in seconds) in the configuration of the voting plugins.
+const timeLimitKey = "tl"
+
+const timeLimitHTML = `
+<p><strong>{{.Translation.TimeRemaining}}: <span id="{{.ID}}"></span></strong></p>
+<script>
+(function() {
+	var end = Date.now() + {{.Remaining}};
+	var e = document.getElementById({{.ID}});
+	var show = function() {
+		var r = Math.max(0, Math.ceil((end - Date.now()) / 1000));
+		e.textContent = Math.floor(r / 60) + ":" + String(r % 60).padStart(2, "0");
+		return r;
+	};
+	show();
+	var i = setInterval(function() {
+		if(!document.body.contains(e) || show() <= 0) {
+			clearInterval(i);
+		}
+	}, 250);
+})();
+</script>
+`
+
+var timeLimitTemplate = template.Must(template.New("timeLimit").Parse(timeLimitHTML))
+
+type timeLimitStruct struct {
+	ID          string
+	Remaining   int64
+	Translation translation.Translation
+}
+
+// timeLimit is the optional time limit of a voting plugin.
+// The server is the authoritative clock: participants only get the remaining time for display.
+// The zero value represents no time limit and is safe to use.
+type timeLimit struct {
+	l        sync.Mutex
+	deadline time.Time
+	timer    *time.Timer
+}
+
+// parseTimeLimit returns the time limit of the configuration or 0 if none is set.
+func parseTimeLimit(input map[string]string) (time.Duration, error) {
+	s := input[timeLimitKey]
+	if s == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("can not parse time limit: %w", err)
+	}
+	if i < 0 {
+		return 0, fmt.Errorf("time limit must not be negative")
+	}
+	return time.Duration(i) * time.Second, nil
+}
+
+// Start starts the time limit. It returns a channel which fires once the time is up.
+// If d is 0, a nil channel is returned, which never fires.
+func (t *timeLimit) Start(d time.Duration) <-chan time.Time {
+	t.l.Lock()
+	defer t.l.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	t.deadline = time.Now().Add(d)
+	t.timer = time.NewTimer(d)
+	return t.timer.C
+}
+
+// Stop stops the time limit. It is safe to call Stop on a stopped or not started time limit.
+func (t *timeLimit) Stop() {
+	t.l.Lock()
+	defer t.l.Unlock()
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	t.deadline = time.Time{}
+}
+
+// HTML returns a countdown showing the remaining time or an empty string if no time limit is active.
+func (t *timeLimit) HTML() template.HTML {
+	t.l.Lock()
+	deadline := t.deadline
+	t.l.Unlock()
+
+	if deadline.IsZero() {
+		return ""
+	}
+
+	remaining := time.Until(deadline)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	td := timeLimitStruct{
+		ID:          fmt.Sprintf("timeLimit_%d", deadline.UnixNano()),
+		Remaining:   remaining.Milliseconds(),
+		Translation: translation.GetDefaultTranslation(),
+	}
+	var buf bytes.Buffer
+	err := timeLimitTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing timeLimit: %s", err.Error())
+	}
+	return template.HTML(buf.Bytes())
+}
@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2023,2025 Marcus Soll
+// Copyright 2023,2025,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -19,7 +19,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"html/template"
 	"log"
 	"math"
@@ -27,9 +27,12 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/Top-Ranger/responsego/registry"
 	"github.com/Top-Ranger/responsego/translation"
+	"github.com/Top-Ranger/responsego/wordlist"
 )
 
 func init() {
@@ -40,10 +43,41 @@ func init() {
 }
 
 const wordcloudConfig = `
-<h1>%s</h1>
-<label for="wc_textarea">%s:</label> <input class="fullwidth" type="text" id="wc_textarea" rows="4" autocomplete="off">
-<p><button onclick="sendActivate('Wordcloud', document.getElementById('wc_textarea').value)">%s</button></p>
-<p><button onclick="saveElement('Wordcloud', document.getElementById('wc_textarea').value, '%s: '+document.getElementById('wc_textarea').value.substring(0,80)+(document.getElementById('wc_textarea').value.length>80?'[...]':''))">%s</button></p>`
+<h1>{{.Translation.DisplayWordcloud}}</h1>
+<label for="wc_textarea">{{.Translation.Title}}:</label> <input class="fullwidth" type="text" id="wc_textarea" rows="4" autocomplete="off"><br>
+<label for="wc_language">{{.Translation.WordlistLanguage}}:</label> <select id="wc_language">
+{{range $i, $e := .Languages}}
+<option value="{{$e}}" {{if eq $e $.Translation.Language}}selected{{end}}>{{$e}}</option>
+{{end}}
+</select><br>
+<input id="wc_stopwords" type="checkbox"> <label for="wc_stopwords">{{.Translation.RemoveStopWords}}</label><br>
+<input id="wc_profanity" type="checkbox" checked> <label for="wc_profanity">{{.Translation.FilterProfanity}}</label><br>
+<input id="wc_review" type="checkbox"> <label for="wc_review">{{.Translation.ReviewBeforeDisplay}}</label><br>
+<label for="wc_limit">{{.Translation.SubmissionLimit}}:</label> <input id="wc_limit" type="number" min="0" value="0"><br>
+<p><button onclick="sendActivate('Wordcloud', wordcloudGetData())">{{.Translation.Activate}}</button></p>
+<p><button onclick="saveElement('Wordcloud', wordcloudGetData(), '{{.Translation.DisplayWordcloud}}: '+document.getElementById('wc_textarea').value.substring(0,80)+(document.getElementById('wc_textarea').value.length>80?'[...]':''))">{{.Translation.SaveElement}}</button></p>
+
+<script>
+function wordcloudGetData() {
+	let data = {
+		"Title": document.getElementById('wc_textarea').value,
+		"Language": document.getElementById('wc_language').value,
+		"StopWords": document.getElementById('wc_stopwords').checked,
+		"Profanity": document.getElementById('wc_profanity').checked,
+		"Review": document.getElementById('wc_review').checked,
+		"Limit": parseInt(document.getElementById('wc_limit').value) || 0,
+	};
+	return JSON.stringify(data);
+}
+</script>
+`
+
+var wordcloudConfigTemplate = template.Must(template.New("wordcloudConfig").Parse(wordcloudConfig))
+
+type wordcloudConfigStruct struct {
+	Languages   []string
+	Translation translation.Translation
+}
 
 var wordcloudHTML = template.Must(template.New("wordcloud").Parse(`
 <h1 id="wc_bug">BUG</h1>
@@ -51,7 +85,18 @@ var wordcloudHTML = template.Must(template.New("wordcloud").Parse(`
 	<canvas id="ctx"></canvas>
 </div>
 <input class="fullwidth" type="text" id="InputData" rows="4" autocomplete="off" maxlength="25">
-<p><button onclick="sendData('Wordcloud', document.getElementById('InputData').value); document.getElementById('InputData').value = ''">{{.Translation.Submit}}</button></p>
+<p><button id="wc_submit" onclick="wordcloudSubmit()">{{.Translation.Submit}}</button></p>
+{{if and (not .Admin) .Limit}}
+<p><em>{{.Translation.RemainingSubmissions}}: <span id="wc_remaining">{{.Limit}}</span></em></p>
+{{end}}
+{{if .Admin}}
+<details>
+<summary>{{.Translation.Moderation}}</summary>
+<p><em>{{.Translation.Filtered}}: <span id="wc_filtered">0</span></em></p>
+<table id="wc_moderation"></table>
+<p>{{.Translation.Banned}}: <span id="wc_banned"></span></p>
+</details>
+{{end}}
 <script>
 var ctx = document.getElementById('ctx').getContext('2d');
 var wc = {
@@ -80,6 +125,28 @@ var wc = {
 };
 var wcChart = new Chart(ctx, wc);
 var wcUpdate = 0;
+var wcModerationUpdate = -1;
+var wcLimit = {{.Limit}};
+var wcSubmitted = 0;
+
+function wordcloudSubmit() {
+	let input = document.getElementById('InputData');
+	if(input.value.trim() === "") {
+		return;
+	}
+	sendData('Wordcloud', input.value);
+	input.value = '';
+{{if not .Admin}}
+	wcSubmitted++;
+	if(wcLimit > 0) {
+		document.getElementById('wc_remaining').textContent = Math.max(0, wcLimit - wcSubmitted);
+		if(wcSubmitted >= wcLimit) {
+			document.getElementById('wc_submit').disabled = true;
+			input.disabled = true;
+		}
+	}
+{{end}}
+}
 
 data_function = function(b) {
 	setTimeout(updateWordcloud(b), 1000);
@@ -95,35 +162,148 @@ function updateWordcloud(b) {
 	  wcUpdate = data.Update
 	  wcChart.update();
 	}
+{{if .Admin}}
+	if(wcModerationUpdate != data.ModerationUpdate) {
+		wcModerationUpdate = data.ModerationUpdate;
+		wordcloudModeration(data);
+	}
+{{end}}
    } catch (e) {
     console.log(e);
    }
 };
+{{if .Admin}}
+function wordcloudCommand(action, word, target) {
+	sendData('Wordcloud', JSON.stringify({"Action": action, "Word": word, "Target": target}));
+}
 
+function wordcloudButton(text, action, word) {
+	let button = document.createElement("BUTTON");
+	button.textContent = text;
+	button.onclick = function() {
+		if(action === "merge") {
+			let target = prompt("{{.Translation.MergeInto}}", "");
+			if(target === null || target.trim() === "") {
+				return;
+			}
+			wordcloudCommand(action, word, target);
+			return;
+		}
+		wordcloudCommand(action, word, "");
+	};
+	return button;
+}
+
+function wordcloudModeration(data) {
+	document.getElementById("wc_filtered").textContent = data.Filtered;
+	let table = document.getElementById("wc_moderation");
+	table.innerHTML = "";
+	for(let i = 0; i < data.Moderation.length; i++) {
+		let e = data.Moderation[i];
+		let tr = document.createElement("TR");
+		let word = document.createElement("TD");
+		word.textContent = e.Word;
+		tr.appendChild(word);
+		let count = document.createElement("TD");
+		count.textContent = e.Count;
+		tr.appendChild(count);
+		let status = document.createElement("TD");
+		switch(e.Status) {
+		case {{.Visible}}:
+			status.textContent = "{{.Translation.Visible}}";
+			break;
+		case {{.Pending}}:
+			status.textContent = "{{.Translation.Pending}}";
+			break;
+		case {{.Hidden}}:
+			status.textContent = "{{.Translation.Hidden}}";
+			break;
+		}
+		tr.appendChild(status);
+		let actions = document.createElement("TD");
+		if(e.Status !== {{.Visible}}) {
+			actions.appendChild(wordcloudButton("{{.Translation.Approve}}", "show", e.Word));
+		}
+		if(e.Status !== {{.Hidden}}) {
+			actions.appendChild(wordcloudButton("{{.Translation.Hide}}", "hide", e.Word));
+		}
+		actions.appendChild(wordcloudButton("{{.Translation.Ban}}", "ban", e.Word));
+		actions.appendChild(wordcloudButton("{{.Translation.Merge}}", "merge", e.Word));
+		tr.appendChild(actions);
+		table.appendChild(tr);
+	}
+	let banned = document.getElementById("wc_banned");
+	banned.innerHTML = "";
+	for(let i = 0; i < data.Banned.length; i++) {
+		let span = document.createElement("SPAN");
+		span.textContent = data.Banned[i] + " ";
+		banned.appendChild(span);
+		banned.appendChild(wordcloudButton("{{.Translation.Unban}}", "unban", data.Banned[i]));
+		banned.appendChild(document.createTextNode(" "));
+	}
+}
+{{end}}
 document.getElementById("wc_bug").classList.add("hidden")
 </script>
 `))
 
 type wordcloudHTMLStruct struct {
 	Title       string
+	Admin       bool
+	Limit       int
+	Visible     int
+	Pending     int
+	Hidden      int
 	Translation translation.Translation
 }
 
+const (
+	wordcloudVisible = iota
+	wordcloudPending
+	wordcloudHidden
+)
+
+// wordcloudMaxLength is the maximum length of a word in runes.
+const wordcloudMaxLength = 50
+
+type wordcloudGetConfig struct {
+	Title     string
+	Language  string
+	StopWords bool
+	Profanity bool
+	Review    bool
+	Limit     int
+}
+
+type wordcloudCommand struct {
+	Action string
+	Word   string
+	Target string
+}
+
 type wordcloud struct {
-	adminHTML  chan<- template.HTML
-	userHTML   chan<- template.HTML
-	adminInput <-chan []byte
-	userInput  <-chan []byte
-	ctx        context.Context
-	cancel     context.CancelFunc
-	adminData  chan<- []byte
-	userData   chan<- []byte
-
-	update       int
-	wordcloudMap map[string]int
-	cacheHTML    template.HTML
-	l            sync.Mutex
-	htmlCache    template.HTML
+	adminHTML        chan<- template.HTML
+	userHTML         chan<- template.HTML
+	adminInput       <-chan []byte
+	userInput        <-chan []byte
+	participantInput <-chan registry.ParticipantInput
+	ctx              context.Context
+	cancel           context.CancelFunc
+	adminData        chan<- []byte
+	userData         chan<- []byte
+
+	config           wordcloudGetConfig
+	update           int
+	moderationUpdate int
+	wordcloudMap     map[string]int
+	status           map[string]int
+	banned           map[string]bool
+	merged           map[string]string // source word -> target word
+	submissions      map[string]int    // participant -> number of submissions
+	filtered         int
+	l                sync.Mutex
+	htmlUser         template.HTML
+	htmlAdmin        template.HTML
 }
 
 type wordcloudUpdate struct {
@@ -132,9 +312,33 @@ type wordcloudUpdate struct {
 	Update int
 }
 
+type wordcloudModeration struct {
+	Word   string
+	Count  int
+	Status int
+}
+
+type wordcloudAdminUpdate struct {
+	wordcloudUpdate
+	ModerationUpdate int
+	Moderation       []wordcloudModeration
+	Banned           []string
+	Filtered         int
+}
+
 func (w *wordcloud) ConfigHTML() (string, template.HTML) {
 	tl := translation.GetDefaultTranslation()
-	return tl.DisplayWordcloud, template.HTML(fmt.Sprintf(wordcloudConfig, template.HTMLEscapeString(tl.DisplayWordcloud), template.HTMLEscapeString(tl.Title), template.HTMLEscapeString(tl.Activate), template.HTMLEscapeString(tl.DisplayWordcloud), template.HTMLEscapeString(tl.SaveElement)))
+	td := wordcloudConfigStruct{
+		Languages:   wordlist.Languages(),
+		Translation: tl,
+	}
+	var buf bytes.Buffer
+	err := wordcloudConfigTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing wordcloud config: %s", err.Error())
+	}
+
+	return tl.DisplayWordcloud, template.HTML(buf.Bytes())
 }
 
 func (w *wordcloud) AdminHTMLChannel(c chan<- template.HTML) {
@@ -157,6 +361,10 @@ func (w *wordcloud) ReceiveUserChannel(c <-chan []byte) {
 	w.userInput = c
 }
 
+func (w *wordcloud) ReceiveUserParticipantChannel(c <-chan registry.ParticipantInput) {
+	w.participantInput = c
+}
+
 func (w *wordcloud) ReceiveAdminChannel(c <-chan []byte) {
 	w.adminInput = c
 }
@@ -164,31 +372,64 @@ func (w *wordcloud) ReceiveAdminChannel(c <-chan []byte) {
 func (w *wordcloud) Activate(by []byte) error {
 	w.l.Lock()
 	defer w.l.Unlock()
+
+	err := json.Unmarshal(by, &w.config)
+	if err != nil {
+		// Old configuration only containing the title
+		w.config = wordcloudGetConfig{Title: string(by)}
+	}
+	if w.config.Limit < 0 {
+		return errors.New("wordcloud: limit must not be negative")
+	}
+	if (w.config.StopWords || w.config.Profanity) && w.config.Language == "" {
+		w.config.Language = translation.GetDefaultTranslation().Language
+	}
+
 	w.ctx = context.Background()
 	w.ctx, w.cancel = context.WithCancel(w.ctx)
 	w.wordcloudMap = make(map[string]int)
+	w.status = make(map[string]int)
+	w.banned = make(map[string]bool)
+	w.merged = make(map[string]string)
+	w.submissions = make(map[string]int)
 
 	go w.wordcloudWorker(w.ctx)
+
+	td := wordcloudHTMLStruct{
+		Title:       w.config.Title,
+		Limit:       w.config.Limit,
+		Visible:     wordcloudVisible,
+		Pending:     wordcloudPending,
+		Hidden:      wordcloudHidden,
+		Translation: translation.GetDefaultTranslation(),
+	}
 	var buf bytes.Buffer
-	err := wordcloudHTML.Execute(&buf, wordcloudHTMLStruct{Title: string(by), Translation: translation.GetDefaultTranslation()})
+	err = wordcloudHTML.Execute(&buf, td)
 	if err != nil {
 		log.Printf("error executing wordcloud: %s", err.Error())
 	}
+	w.htmlUser = template.HTML(buf.Bytes())
 
-	b := template.HTML(buf.Bytes())
-	w.htmlCache = b
-	w.adminHTML <- b
-	w.userHTML <- b
+	buf.Reset()
+	td.Admin = true
+	err = wordcloudHTML.Execute(&buf, td)
+	if err != nil {
+		log.Printf("error executing wordcloud: %s", err.Error())
+	}
+	w.htmlAdmin = template.HTML(buf.Bytes())
+
+	w.adminHTML <- w.htmlAdmin
+	w.userHTML <- w.htmlUser
 
 	return nil
 }
 
 func (w *wordcloud) GetLastHTMLUser() template.HTML {
-	return w.htmlCache
+	return w.htmlUser
 }
 
 func (w *wordcloud) GetLastHTMLAdmin() template.HTML {
-	return w.htmlCache
+	return w.htmlAdmin
 }
 
 func (w *wordcloud) Deactivate() {
@@ -199,6 +440,130 @@ func (w *wordcloud) Deactivate() {
 	}
 }
 
+// normaliseWord converts a submission into its canonical form.
+// It lower cases the word, removes punctuation and symbols at both ends and collapses white space.
+func normaliseWord(word string) string {
+	word = strings.ToLower(word)
+	word = strings.Join(strings.Fields(word), " ")
+	word = strings.TrimFunc(word, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r)
+	})
+	return word
+}
+
+// resolve returns the word a submission should be counted for. Caller must hold l.
+func (w *wordcloud) resolve(word string) string {
+	if target, ok := w.merged[word]; ok {
+		return target
+	}
+	return word
+}
+
+// filter returns whether a word should be dropped because of the stop-word or profanity lists.
+func (w *wordcloud) filter(word string) bool {
+	words := strings.Fields(word)
+	if w.config.Profanity {
+		if wordlist.IsProfanity(w.config.Language, word) {
+			return true
+		}
+		for i := range words {
+			if wordlist.IsProfanity(w.config.Language, strings.TrimFunc(words[i], unicode.IsPunct)) {
+				return true
+			}
+		}
+	}
+	if w.config.StopWords {
+		for i := range words {
+			if !wordlist.IsStopWord(w.config.Language, strings.TrimFunc(words[i], unicode.IsPunct)) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// addWord adds a submission. Submissions by an admin are neither limited nor reviewed. Caller must hold l.
+func (w *wordcloud) addWord(word, participant string, admin bool) {
+	word = normaliseWord(word)
+	if word == "" || utf8.RuneCountInString(word) > wordcloudMaxLength {
+		return
+	}
+
+	if !admin && w.config.Limit > 0 {
+		if w.submissions[participant] >= w.config.Limit {
+			return
+		}
+		w.submissions[participant]++
+	}
+
+	word = w.resolve(word)
+	if w.banned[word] || w.filter(word) {
+		w.filtered++
+		w.moderationUpdate++
+		return
+	}
+
+	status, ok := w.status[word]
+	if !ok {
+		status = wordcloudVisible
+		if w.config.Review && !admin {
+			status = wordcloudPending
+		}
+		w.status[word] = status
+	}
+	w.wordcloudMap[word]++
+	w.moderationUpdate++
+	if status == wordcloudVisible {
+		w.update++
+	}
+}
+
+// moderate applies a moderation command of an admin. Caller must hold l.
+func (w *wordcloud) moderate(c wordcloudCommand) {
+	word := normaliseWord(c.Word)
+	switch c.Action {
+	case "show":
+		if _, ok := w.status[word]; ok {
+			w.status[word] = wordcloudVisible
+		}
+	case "hide":
+		if _, ok := w.status[word]; ok {
+			w.status[word] = wordcloudHidden
+		}
+	case "ban":
+		w.banned[word] = true
+		delete(w.wordcloudMap, word)
+		delete(w.status, word)
+	case "unban":
+		delete(w.banned, word)
+	case "merge":
+		target := w.resolve(normaliseWord(c.Target))
+		if target == "" || target == word {
+			return
+		}
+		if _, ok := w.status[word]; !ok {
+			return
+		}
+		if _, ok := w.status[target]; !ok {
+			w.status[target] = w.status[word]
+		}
+		w.wordcloudMap[target] += w.wordcloudMap[word]
+		delete(w.wordcloudMap, word)
+		delete(w.status, word)
+		for k := range w.merged {
+			if w.merged[k] == word {
+				w.merged[k] = target
+			}
+		}
+		w.merged[word] = target
+	default:
+		return
+	}
+	w.update++
+	w.moderationUpdate++
+}
+
 func (w *wordcloud) wordcloudWorker(ctx context.Context) {
 	done := w.ctx.Done()
 	ticker := time.NewTicker(5 * time.Second)
@@ -206,75 +571,142 @@ func (w *wordcloud) wordcloudWorker(ctx context.Context) {
 	for {
 		select {
 		case b := <-w.adminInput:
+			var c wordcloudCommand
+			err := json.Unmarshal(b, &c)
 			w.l.Lock()
-			word := string(b)
-			word = strings.ToLower(word)
-			word = strings.TrimSpace(word)
-			if word != "" {
-				i, _ := w.wordcloudMap[word]
-				w.wordcloudMap[word] = i + 1
-				w.update++
+			if err == nil && c.Action != "" {
+				w.moderate(c)
+				w.l.Unlock()
+				w.sendUpdate()
+				continue
 			}
+			w.addWord(string(b), "", true)
 			w.l.Unlock()
 		case b := <-w.userInput:
+			// Should not happen since participant input is received - handle anyway
 			w.l.Lock()
-			word := string(b)
-			word = strings.ToLower(word)
-			word = strings.TrimSpace(word)
-			if word != "" {
-				i, _ := w.wordcloudMap[word]
-				w.wordcloudMap[word] = i + 1
-				w.update++
-			}
+			w.addWord(string(b), "", false)
 			w.l.Unlock()
-		case <-ticker.C:
+		case p := <-w.participantInput:
 			w.l.Lock()
-			wu := wordcloudUpdate{
-				Labels: make([]string, 0, len(w.wordcloudMap)),
-				Data:   make([]int, 0, len(w.wordcloudMap)),
-				Update: w.update,
-			}
-			max := 0
-			for k, v := range w.wordcloudMap {
-				if v > max {
-					max = v
-				}
-				wu.Labels = append(wu.Labels, k)
-				wu.Data = append(wu.Data, v)
-			}
+			w.addWord(string(p.Data), p.Participant, false)
 			w.l.Unlock()
-			if max <= 1 { // Needed due to log(1) = 0
-				max = 2
-			}
-			factor := 36 / math.Log2(float64(max))
-			for i := range wu.Data {
-				if wu.Data[i] <= 1 { // Needed due to log(1) = 0
-					wu.Data[i] = 2
-				}
-				wu.Data[i] = int(math.Log2(float64(wu.Data[i])) * factor)
-				if wu.Data[i] == 0 {
-					wu.Data[i] = 1
-				}
-			}
-			sort.Sort(&wu)
-			j, err := json.Marshal(wu)
-			if err != nil {
-				log.Printf("wordcloud: Error marshaling update: (%s)", err.Error())
-				continue
-			}
-			w.adminData <- j
-			w.userData <- j
+		case <-ticker.C:
+			w.sendUpdate()
 		case <-done:
 			return
 		}
 	}
 }
 
+// sendUpdate sends the visible words to all participants and the visible words plus moderation data to all admins.
+func (w *wordcloud) sendUpdate() {
+	w.l.Lock()
+	wu := wordcloudUpdate{
+		Labels: make([]string, 0, len(w.wordcloudMap)),
+		Data:   make([]int, 0, len(w.wordcloudMap)),
+		Update: w.update,
+	}
+	au := wordcloudAdminUpdate{
+		ModerationUpdate: w.moderationUpdate,
+		Moderation:       make([]wordcloudModeration, 0, len(w.wordcloudMap)),
+		Banned:           make([]string, 0, len(w.banned)),
+		Filtered:         w.filtered,
+	}
+	max := 0
+	for k, v := range w.wordcloudMap {
+		au.Moderation = append(au.Moderation, wordcloudModeration{Word: k, Count: v, Status: w.status[k]})
+		if w.status[k] != wordcloudVisible {
+			continue
+		}
+		if v > max {
+			max = v
+		}
+		wu.Labels = append(wu.Labels, k)
+		wu.Data = append(wu.Data, v)
+	}
+	for k := range w.banned {
+		au.Banned = append(au.Banned, k)
+	}
+	w.l.Unlock()
+
+	if max <= 1 { // Needed due to log(1) = 0
+		max = 2
+	}
+	factor := 36 / math.Log2(float64(max))
+	for i := range wu.Data {
+		if wu.Data[i] <= 1 { // Needed due to log(1) = 0
+			wu.Data[i] = 2
+		}
+		wu.Data[i] = int(math.Log2(float64(wu.Data[i])) * factor)
+		if wu.Data[i] == 0 {
+			wu.Data[i] = 1
+		}
+	}
+	sort.Sort(&wu)
+	sort.Slice(au.Moderation, func(i, j int) bool {
+		if au.Moderation[i].Status != au.Moderation[j].Status {
+			return au.Moderation[i].Status == wordcloudPending // Pending words first
+		}
+		if au.Moderation[i].Count != au.Moderation[j].Count {
+			return au.Moderation[i].Count > au.Moderation[j].Count
+		}
+		return au.Moderation[i].Word < au.Moderation[j].Word
+	})
+	sort.Strings(au.Banned)
+	au.wordcloudUpdate = wu
+
+	j, err := json.Marshal(wu)
+	if err != nil {
+		log.Printf("wordcloud: Error marshaling update: (%s)", err.Error())
+		return
+	}
+	ja, err := json.Marshal(au)
+	if err != nil {
+		log.Printf("wordcloud: Error marshaling admin update: (%s)", err.Error())
+		return
+	}
+	w.adminData <- ja
+	w.userData <- j
+}
+
+type wordcloudResultStruct struct {
+	Words    map[string]int
+	Pending  map[string]int
+	Hidden   map[string]int
+	Banned   []string
+	Merged   map[string]string
+	Filtered int
+}
+
 func (w *wordcloud) GetAdminDownload() []byte {
 	w.l.Lock()
 	defer w.l.Unlock()
 
-	b, err := json.Marshal(w.wordcloudMap)
+	r := wordcloudResultStruct{
+		Words:    make(map[string]int),
+		Pending:  make(map[string]int),
+		Hidden:   make(map[string]int),
+		Banned:   make([]string, 0, len(w.banned)),
+		Merged:   w.merged,
+		Filtered: w.filtered,
+	}
+	for k, v := range w.wordcloudMap {
+		switch w.status[k] {
+		case wordcloudVisible:
+			r.Words[k] = v
+		case wordcloudPending:
+			r.Pending[k] = v
+		case wordcloudHidden:
+			r.Hidden[k] = v
+		}
+	}
+	for k := range w.banned {
+		r.Banned = append(r.Banned, k)
+	}
+	sort.Strings(r.Banned)
+
+	b, err := json.Marshal(r)
 	if err != nil {
 		return []byte(err.Error())
 	}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitDefault is the key in ConfigStruct.RateLimits used for all actions without an own limit.
+const rateLimitDefault = "default"
+
+// rateLimitViolationWindow is the time after which the number of rejected messages of a connection is reset.
+const rateLimitViolationWindow = time.Minute
+
+// RateLimitConfig is the token bucket configuration of a single action.
+// PerSecond is the number of messages refilled each second, Burst the maximum number of messages at once.
+// A PerSecond of 0 disables the limit.
+type RateLimitConfig struct {
+	PerSecond float64
+	Burst     int
+}
+
+// defaultRateLimitIPFactor is used if ConfigStruct.RateLimitIPFactor is not set.
+const defaultRateLimitIPFactor = 10
+
+// defaultRateLimits are used if no limits are configured.
+var defaultRateLimits = map[string]RateLimitConfig{
+	actionIcon:       {PerSecond: 2, Burst: 5},
+	actionUserUpdate: {PerSecond: 5, Burst: 20},
+	rateLimitDefault: {PerSecond: 5, Burst: 20},
+}
+
+// rateLimiter holds the token buckets of a participant or an IP address.
+// It is safe for concurrent use, since a participant might have multiple connections.
+type rateLimiter struct {
+	limiters map[string]*rate.Limiter // not modified after creation
+}
+
+// newRateLimiter returns a rateLimiter with one token bucket per configured action.
+// All limits are multiplied by factor.
+func newRateLimiter(factor float64) *rateLimiter {
+	limits := config.RateLimits
+	if limits == nil {
+		limits = defaultRateLimits
+	}
+
+	r := &rateLimiter{limiters: make(map[string]*rate.Limiter, len(limits))}
+	for action, c := range limits {
+		if c.PerSecond <= 0 {
+			r.limiters[action] = rate.NewLimiter(rate.Inf, 0)
+			continue
+		}
+		burst := int(float64(c.Burst) * factor)
+		if burst < 1 {
+			burst = 1
+		}
+		r.limiters[action] = rate.NewLimiter(rate.Limit(c.PerSecond*factor), burst)
+	}
+	return r
+}
+
+// newIPRateLimiter returns a rateLimiter for all connections from a single IP address.
+// Its limits are higher than the limits of a participant since several participants might share an address.
+func newIPRateLimiter() *rateLimiter {
+	factor := config.RateLimitIPFactor
+	if factor <= 0 {
+		factor = defaultRateLimitIPFactor
+	}
+	return newRateLimiter(factor)
+}
+
+// reserve takes a token for a message with the given action at time now.
+// Actions without an own limit use the default limit. If there is no default limit, nil is returned.
+// The message may only be processed if the reservation is OK and has no delay, otherwise the reservation must be cancelled.
+func (r *rateLimiter) reserve(action string, now time.Time) *rate.Reservation {
+	l, ok := r.limiters[action]
+	if !ok {
+		l, ok = r.limiters[rateLimitDefault]
+	}
+	if !ok {
+		return nil
+	}
+	return l.ReserveN(now, 1)
+}
+
+// connectionLimit tracks the rejected messages of a single connection.
+// A message is only allowed if all limiters allow it.
+type connectionLimit struct {
+	limiters []*rateLimiter
+	ip       string
+	rejected int
+	since    time.Time
+}
+
+// allow returns whether the message b may be processed.
+// The second return value is true if the connection exceeded the configured number of rejected messages and should be closed.
+func (c *connectionLimit) allow(b []byte) (bool, bool) {
+	var m struct {
+		Action string
+	}
+	// Invalid messages are passed on so that they are logged by the response
+	json.Unmarshal(b, &m)
+
+	// Check all limiters first so that a rejected message does not use up tokens of any limiter
+	now := time.Now()
+	allowed := true
+	reservations := make([]*rate.Reservation, 0, len(c.limiters))
+	for _, l := range c.limiters {
+		r := l.reserve(m.Action, now)
+		if r == nil {
+			continue
+		}
+		reservations = append(reservations, r)
+		if !r.OK() || r.DelayFrom(now) > 0 {
+			allowed = false
+			break
+		}
+	}
+	if allowed {
+		return true, false
+	}
+	for _, r := range reservations {
+		r.CancelAt(now)
+	}
+
+	if now.Sub(c.since) > rateLimitViolationWindow {
+		c.rejected = 0
+		c.since = now
+	}
+	c.rejected++
+	return false, config.RateLimitDisconnect > 0 && c.rejected >= config.RateLimitDisconnect
+}
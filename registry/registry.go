@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020,2023,2025 Marcus Soll
+// Copyright 2020,2023,2025,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -57,6 +57,58 @@ type DownloadResultPlugin interface {
 	GetAdminDownload() []byte
 }
 
+// DownloadFormatPlugin is an extended version of DownloadResultPlugin offering the results in additional formats.
+// DownloadFormats returns the file extensions of all additional formats (e.g. "csv").
+// GetAdminDownloadFormat returns the results in one of these formats.
+// The format "html" must return a HTML fragment, which is embedded into a complete document including chart.js, moment.js and chartjs-adapter-moment.
+type DownloadFormatPlugin interface {
+	DownloadResultPlugin
+	DownloadFormats() []string
+	GetAdminDownloadFormat(format string) []byte
+}
+
+// ParticipantInput represents a single input of a participant.
+// Participant is a pseudonymous identifier of the participant. It stays the same across reconnects as long as the browser keeps its cookies.
+type ParticipantInput struct {
+	Participant string
+	Data        []byte
+}
+
+// ParticipantFeedbackPlugin is an extended version of FeedbackPlugin which knows which participant send an input.
+// If a plugin implements this interface, all participant inputs are send to the channel provided by ReceiveUserParticipantChannel instead of the one provided by ReceiveUserChannel.
+type ParticipantFeedbackPlugin interface {
+	FeedbackPlugin
+	ReceiveUserParticipantChannel(<-chan ParticipantInput)
+}
+
+// ParticipantOutput represents data which is only send to a single participant.
+// The data is send to all connections of the participant.
+type ParticipantOutput struct {
+	Participant string
+	Data        []byte
+}
+
+// ParticipantDataFeedbackPlugin is an extended version of ParticipantFeedbackPlugin allowing to send data to a single participant without replacing the HTML page
+type ParticipantDataFeedbackPlugin interface {
+	ParticipantFeedbackPlugin
+	UserParticipantDataChannel(chan<- ParticipantOutput)
+}
+
+// GroupingPlugin is an extended version of FeedbackPlugin which assigns participants to named groups.
+// The groups can be used to activate a plugin separately for each group (breakout rooms).
+// GetGroups returns a map of participant to group name. Participants without a group are not included.
+type GroupingPlugin interface {
+	FeedbackPlugin
+	GetGroups() map[string]string
+}
+
+// ClosablePlugin is an extended version of FeedbackPlugin which can be closed by the presenter (e.g. a poll which shows its results after closing).
+// IsClosed must be safely callable in parallel.
+type ClosablePlugin interface {
+	FeedbackPlugin
+	IsClosed() bool
+}
+
 // Authenticater allows to validate a username/password combination.
 // It can safely be assumed that LoadConfig will only be called once before Authenticate will be called.
 // Authenticate must be safely callable in parallel.
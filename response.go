@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020,2023,2025 Marcus Soll
+// Copyright 2020,2023,2025,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -16,17 +16,21 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"html/template"
 	"log"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/Top-Ranger/responsego/helper"
 	"github.com/Top-Ranger/responsego/registry"
 	"github.com/Top-Ranger/responsego/translation"
 	"github.com/gorilla/websocket"
@@ -48,24 +52,37 @@ func init() {
 
 const (
 	actionActivate      = "activate"
+	actionBreakout      = "activatebreakout"
 	actionUserUpdate    = "user"
 	actionAdminUpdate   = "admin"
 	actionResetIcons    = "resetIcon"
 	actionIcon          = "icon"
+	actionSetIcons      = "seticons"
+	actionIconMode      = "iconmode"
+	actionIconDownload  = "icondownload"
+	actionIconThreshold = "iconthreshold"
+	actionAgendaSet     = "agendaset"
+	actionAgendaNext    = "agendanext"
+	actionAgendaPrev    = "agendaprevious"
+	actionAgendaGoto    = "agendagoto"
+	actionAgendaStop    = "agendastop"
 	actionHTML          = "html"
 	actionData          = "data"
 	actionAdminDownload = "admindownload"
 )
 
 const (
-	iconSlower      = "slower"
-	iconBreak       = "break"
-	iconFaster      = "faster"
-	iconQuestion    = "question"
-	iconGood        = "good"
+	icons           = "icons"
+	iconCount       = "iconcount"
+	iconActivity    = "icontimeline"
+	iconEventData   = "icondownload"
+	iconAlertData   = "iconalert"
+	agendaData      = "agenda"
 	numberConnected = "connected"
 	downloadData    = "download"
+	downloadFormat  = "downloadformat"
 	canDownload     = "candownload"
+	errorMessage    = "error"
 )
 
 const globalAction = "_global"
@@ -87,6 +104,81 @@ type message struct {
 	From   string
 	Action string
 	Data   string
+	Room   string `json:",omitempty"`
+}
+
+// formatDownload is send to an admin requesting the results in an additional format.
+type formatDownload struct {
+	Format string
+	Data   string
+}
+
+// downloadDocumentScripts are embedded into HTML downloads so charts also work offline.
+var downloadDocumentScripts = []string{"js/moment-with-locales-2.29.4.min.js", "js/chart-3.9.1.min.js", "js/chartjs-adapter-moment-1.0.1.min.js"}
+
+var downloadDocumentTemplate = template.Must(template.New("downloadDocument").Parse(`<!DOCTYPE HTML>
+<html>
+<head>
+<meta charset="UTF-8">
+<title>{{.Title}}</title>
+{{range .Scripts}}<script>{{.}}</script>
+{{end}}<style>.chart { max-width: 800px; }</style>
+</head>
+<body>
+{{.Body}}
+</body>
+</html>
+`))
+
+type downloadDocumentStruct struct {
+	Title   string
+	Scripts []template.JS
+	Body    template.HTML
+}
+
+// downloadDocument wraps a HTML fragment returned by a plugin into a standalone document.
+func downloadDocument(title string, fragment []byte) ([]byte, error) {
+	td := downloadDocumentStruct{
+		Title: title,
+		Body:  template.HTML(fragment),
+	}
+	for _, name := range downloadDocumentScripts {
+		b, err := cachedFiles.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		td.Scripts = append(td.Scripts, template.JS(b))
+	}
+	var buf bytes.Buffer
+	err := downloadDocumentTemplate.Execute(&buf, td)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type iconCountUpdate struct {
+	Name      string
+	Count     float64
+	Mode      string
+	Minutes   float64
+	Threshold iconThreshold
+}
+
+type iconModeRequest struct {
+	Name    string // empty for all icons
+	Mode    string
+	Minutes float64
+}
+
+type iconThresholdRequest struct {
+	Name string // empty for all icons
+	iconThreshold
+}
+
+type iconSetRequest struct {
+	Preset string
+	Icons  []icon
 }
 
 type readMessage struct {
@@ -100,9 +192,14 @@ type response struct {
 	Stop     context.CancelFunc
 	Password string
 	Path     string
+	Owner    string // authenticated user who created the response, empty if no authentication is required
 
 	admins            map[int]chan<- []byte
 	users             map[int]chan<- []byte
+	participants      map[int]string
+	ips               map[int]string
+	limiters          map[string]*rateLimiter // participant -> rate limit
+	ipLimiters        map[string]*rateLimiter // ip -> rate limit
 	currentID         int
 	currentPluginName string
 	currentPlugin     registry.FeedbackPlugin
@@ -114,12 +211,28 @@ type response struct {
 	userData          chan []byte
 	adminInput        chan []byte
 	userInput         chan []byte
+	participantInput  chan registry.ParticipantInput
+	participantData   chan registry.ParticipantOutput
+	images            *imageStore
+
+	groups             map[string]string // participant -> group name of the last grouping plugin
+	breakouts          []*breakoutRoom
+	breakoutPluginName string
 
-	nSlower   int
-	nBreak    int
-	nFaster   int
-	nQuestion int
-	nGood     int
+	icons               []icon
+	iconCounts          map[string]int
+	iconModes           map[string]iconCounterMode
+	iconEvents          []iconEvent
+	iconReset           time.Time
+	iconTimelineChanged bool
+	iconThresholds      map[string]iconThreshold
+	iconAlerted         map[string]bool // alerts are only send once until the counters are reset
+
+	agenda         []agendaItem
+	agendaPosition int
+	agendaPlugin   registry.FeedbackPlugin // plugin activated by the agenda, automatic advancing stops if it is replaced manually
+	agendaSince    time.Time               // activation of the current element or closing of the poll
+	agendaClosed   bool
 }
 
 type userTemplateStruct struct {
@@ -135,11 +248,15 @@ type adminTemplateStruct struct {
 		Name string
 		HTML template.HTML
 	}
+	IconPresets []iconPresetOption
+	StaticIcons []string
+	Library     bool
 	Translation translation.Translation
 	ServerPath  string
 }
 
-func websocketReader(stopWriter context.CancelFunc, target chan<- readMessage, ws *websocket.Conn, r *response, id int) {
+// websocketReader reads all messages of a connection. If limit is not nil, messages exceeding the rate limit are dropped.
+func websocketReader(stopWriter context.CancelFunc, target chan<- readMessage, ws *websocket.Conn, r *response, id int, limit *connectionLimit) {
 	defer stopWriter()
 	for {
 		_, b, err := ws.ReadMessage()
@@ -149,6 +266,16 @@ func websocketReader(stopWriter context.CancelFunc, target chan<- readMessage, w
 			}
 			return
 		}
+		if limit != nil {
+			ok, disconnect := limit.allow(b)
+			if disconnect {
+				log.Printf("socket read (%s): disconnecting %s after %d rate limited messages", r.Path, limit.ip, limit.rejected)
+				return
+			}
+			if !ok {
+				continue
+			}
+		}
 		t := time.NewTimer(time.Second)
 		select {
 		case target <- readMessage{ID: id, message: b}:
@@ -184,10 +311,41 @@ func (r *response) removeWS(id int) {
 	defer r.l.Unlock()
 	delete(r.admins, id)
 	delete(r.users, id)
+
+	// Remove rate limits once the last connection of a participant or ip is closed
+	participant, isUser := r.participants[id]
+	ip := r.ips[id]
+	delete(r.participants, id)
+	delete(r.ips, id)
+	if !isUser {
+		return
+	}
+	participantConnected, ipConnected := false, false
+	for k := range r.participants {
+		if r.participants[k] == participant {
+			participantConnected = true
+		}
+		if r.ips[k] == ip {
+			ipConnected = true
+		}
+	}
+	if !participantConnected {
+		delete(r.limiters, participant)
+	}
+	if !ipConnected {
+		delete(r.ipLimiters, ip)
+	}
 }
 
 // NewResponse creates a new response object (including startup of all required goroutines).
-func NewResponse(path, password string) *response {
+// iconPreset is the name of the quick feedback icon set. If it does not exist, the default set is used.
+// owner is the authenticated user creating the response and may be empty.
+func NewResponse(path, password, iconPreset, owner string) *response {
+	i, ok := getIconPreset(iconPreset)
+	if !ok {
+		i, _ = getIconPreset(defaultIconPreset)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	r := &response{
 		l:        sync.Mutex{},
@@ -195,13 +353,28 @@ func NewResponse(path, password string) *response {
 		Stop:     cancel,
 		Password: password,
 		Path:     path,
+		Owner:    owner,
 
 		admins:            make(map[int]chan<- []byte),
 		users:             make(map[int]chan<- []byte),
+		participants:      make(map[int]string),
+		ips:               make(map[int]string),
+		limiters:          make(map[string]*rateLimiter),
+		ipLimiters:        make(map[string]*rateLimiter),
 		currentID:         0,
 		currentPluginName: "",
 		readUser:          make(chan readMessage, bufferSize),
 		readAdmins:        make(chan readMessage, bufferSize),
+		images:            newImageStore(config.UploadPath),
+		icons:             i,
+		iconCounts:        make(map[string]int),
+		iconModes:         make(map[string]iconCounterMode),
+		iconEvents:        make([]iconEvent, 0),
+		iconReset:         time.Now(),
+		iconThresholds:    make(map[string]iconThreshold),
+		iconAlerted:       make(map[string]bool),
+		agenda:            make([]agendaItem, 0),
+		agendaPosition:    -1,
 	}
 
 	go r.responseMain()
@@ -209,16 +382,49 @@ func NewResponse(path, password string) *response {
 	return r
 }
 
-func (r *response) AddUser(ws *websocket.Conn) {
+// AddUser adds a participant connection.
+// participant is the pseudonymous identifier of the participant.
+// All connections of a participant share the same rate limit. Additionally, all connections from the same ip share a higher rate limit.
+func (r *response) AddUser(ws *websocket.Conn, participant, ip string) {
 	r.l.Lock()
 	defer r.l.Unlock()
 
 	w := make(chan []byte, bufferSize)
 	r.users[r.currentID] = w
+	r.participants[r.currentID] = participant
+	r.ips[r.currentID] = ip
+	limiters := make([]*rateLimiter, 0, 2)
+	if participant != "" {
+		limiter, ok := r.limiters[participant]
+		if !ok {
+			limiter = newRateLimiter(1)
+			r.limiters[participant] = limiter
+		}
+		limiters = append(limiters, limiter)
+	}
+	ipLimiter, ok := r.ipLimiters[ip]
+	if !ok {
+		ipLimiter = newIPRateLimiter()
+		r.ipLimiters[ip] = ipLimiter
+	}
+	limiters = append(limiters, ipLimiter)
 	ctx, close := context.WithCancel(context.Background())
-	go websocketReader(close, r.readUser, ws, r, r.currentID)
+	go websocketReader(close, r.readUser, ws, r, r.currentID, &connectionLimit{limiters: limiters, ip: ip, since: time.Now()})
 	go websocketWriter(ctx, w, ws, r, r.currentID)
 	r.currentID++
+	r.sendIcons(w)
+	if len(r.breakouts) != 0 {
+		m := message{From: r.breakoutPluginName, Action: actionHTML, Data: string(r.breakoutUserHTML(participant))}
+		b, err := json.Marshal(&m)
+		if err != nil {
+			log.Printf("user HTML (%s) breakout %s: %s", r.Path, r.breakoutPluginName, err.Error())
+		} else {
+			select {
+			case w <- []byte(b):
+			default:
+			}
+		}
+	}
 	if r.currentPlugin != nil {
 		m := message{From: r.currentPluginName, Action: actionHTML, Data: string(r.currentPlugin.GetLastHTMLUser())}
 		b, err := json.Marshal(&m)
@@ -240,7 +446,7 @@ func (r *response) AddAdmin(ws *websocket.Conn) {
 	w := make(chan []byte, bufferSize)
 	r.admins[r.currentID] = w
 	ctx, close := context.WithCancel(context.Background())
-	go websocketReader(close, r.readAdmins, ws, r, r.currentID)
+	go websocketReader(close, r.readAdmins, ws, r, r.currentID, nil)
 	go websocketWriter(ctx, w, ws, r, r.currentID)
 	r.currentID++
 	if r.currentPlugin != nil {
@@ -255,12 +461,27 @@ func (r *response) AddAdmin(ws *websocket.Conn) {
 			}
 		}
 	}
-	r.sendIconUpdate(iconSlower, r.nSlower)
-	r.sendIconUpdate(iconBreak, r.nBreak)
-	r.sendIconUpdate(iconFaster, r.nFaster)
-	r.sendIconUpdate(iconQuestion, r.nQuestion)
-	r.sendIconUpdate(iconGood, r.nGood)
-	r.sendIconUpdate(numberConnected, len(r.users))
+	if len(r.breakouts) != 0 {
+		messages := r.breakoutAdminMessages()
+		for i := range messages {
+			b, err := json.Marshal(&messages[i])
+			if err != nil {
+				log.Printf("admin HTML (%s) breakout %s: %s", r.Path, r.breakoutPluginName, err.Error())
+				continue
+			}
+			select {
+			case w <- []byte(b):
+			default:
+			}
+		}
+	}
+	r.sendIcons(w)
+	for i := range r.icons {
+		r.sendIconUpdate(r.icons[i].Name)
+	}
+	r.sendIconTimeline(w)
+	r.sendConnectedUpdate()
+	r.sendAgendaStatus()
 }
 
 func (r *response) HasUser() bool {
@@ -280,7 +501,7 @@ func (r *response) WriteUserPage(rw http.ResponseWriter) {
 	}
 }
 
-func (r *response) WriteAdminPage(rw http.ResponseWriter) {
+func (r *response) WriteAdminPage(rw http.ResponseWriter, req *http.Request) {
 	fetchConfigCache()
 	url := fmt.Sprintf("%s/%s", config.ServerName, r.Path)
 	qr, err := GenerateQRSrc(url)
@@ -291,12 +512,20 @@ func (r *response) WriteAdminPage(rw http.ResponseWriter) {
 		textTemplate.Execute(rw, t)
 		return
 	}
+	tl := translation.GetDefaultTranslation()
+	staticIconURLs := make([]string, len(staticIcons))
+	for i := range staticIcons {
+		staticIconURLs[i] = staticIconURL(staticIcons[i])
+	}
 	td := adminTemplateStruct{
 		URL:         url,
 		QR:          template.URL(qr),
 		Password:    r.Password,
 		Elements:    pluginConfigCache,
-		Translation: translation.GetDefaultTranslation(),
+		IconPresets: iconPresetOptions(tl),
+		StaticIcons: staticIconURLs,
+		Library:     r.libraryAccess(req),
+		Translation: tl,
 		ServerPath:  config.ServerPath,
 	}
 	err = adminTemplate.Execute(rw, td)
@@ -310,6 +539,8 @@ func (r *response) responseMain() {
 
 	updateUserTicker := time.NewTicker(5 * time.Second)
 	defer updateUserTicker.Stop()
+	agendaTicker := time.NewTicker(time.Second)
+	defer agendaTicker.Stop()
 
 	done := r.ctx.Done()
 	for {
@@ -327,17 +558,156 @@ func (r *response) responseMain() {
 				}
 				switch m.Action {
 				case actionResetIcons:
-					r.nSlower = 0
-					r.nBreak = 0
-					r.nFaster = 0
-					r.nQuestion = 0
-					r.nGood = 0
-					r.sendIconUpdate(iconSlower, r.nSlower)
-					r.sendIconUpdate(iconBreak, r.nBreak)
-					r.sendIconUpdate(iconFaster, r.nFaster)
-					r.sendIconUpdate(iconQuestion, r.nQuestion)
-					r.sendIconUpdate(iconGood, r.nGood)
+					r.iconCounts = make(map[string]int, len(r.icons))
+					r.iconReset = time.Now()
+					r.iconAlerted = make(map[string]bool, len(r.icons))
+					for i := range r.icons {
+						r.sendIconUpdate(r.icons[i].Name)
+					}
+				case actionSetIcons:
+					var set iconSetRequest
+					err := json.Unmarshal([]byte(m.Data), &set)
+					if err != nil {
+						log.Printf("set icons (%s): can not parse '%s': %s", r.Path, m.Data, err.Error())
+						return
+					}
+					if set.Preset != "" {
+						i, ok := getIconPreset(set.Preset)
+						if !ok {
+							log.Printf("set icons (%s): unknown preset %s", r.Path, set.Preset)
+							return
+						}
+						set.Icons = i
+					} else {
+						err = validateIcons(set.Icons)
+						if err != nil {
+							log.Printf("set icons (%s): %s", r.Path, err.Error())
+							return
+						}
+					}
+					r.icons = set.Icons
+					r.iconCounts = make(map[string]int, len(r.icons))
+					r.iconModes = make(map[string]iconCounterMode, len(r.icons))
+					r.iconReset = time.Now()
+					r.iconThresholds = make(map[string]iconThreshold, len(r.icons))
+					r.iconAlerted = make(map[string]bool, len(r.icons))
+					for k := range r.users {
+						r.sendIcons(r.users[k])
+					}
+					for k := range r.admins {
+						r.sendIcons(r.admins[k])
+					}
+					for i := range r.icons {
+						r.sendIconUpdate(r.icons[i].Name)
+					}
+					r.sendConnectedUpdate()
+				case actionIconMode:
+					var req iconModeRequest
+					err := json.Unmarshal([]byte(m.Data), &req)
+					if err != nil {
+						log.Printf("icon mode (%s): can not parse '%s': %s", r.Path, m.Data, err.Error())
+						return
+					}
+					mode := iconCounterMode{Mode: req.Mode, Minutes: req.Minutes}
+					err = mode.validate()
+					if err != nil {
+						log.Printf("icon mode (%s): %s", r.Path, err.Error())
+						return
+					}
+					for i := range r.icons {
+						if req.Name != "" && req.Name != r.icons[i].Name {
+							continue
+						}
+						r.iconModes[r.icons[i].Name] = mode
+						r.sendIconUpdate(r.icons[i].Name)
+					}
+				case actionIconThreshold:
+					var req iconThresholdRequest
+					err := json.Unmarshal([]byte(m.Data), &req)
+					if err != nil {
+						log.Printf("icon threshold (%s): can not parse '%s': %s", r.Path, m.Data, err.Error())
+						return
+					}
+					if req.Value < 0 || math.IsNaN(req.Value) || math.IsInf(req.Value, 0) {
+						log.Printf("icon threshold (%s): invalid value %f", r.Path, req.Value)
+						return
+					}
+					for i := range r.icons {
+						if req.Name != "" && req.Name != r.icons[i].Name {
+							continue
+						}
+						r.iconThresholds[r.icons[i].Name] = req.iconThreshold
+						r.sendIconUpdate(r.icons[i].Name)
+					}
+				case actionIconDownload:
+					c, ok := r.admins[b.ID]
+					if ok {
+						m := message{From: globalAction, Action: iconEventData, Data: string(iconEventsCSV(r.iconEvents))}
+						b, err := json.Marshal(m)
+						if err != nil {
+							log.Printf("sending icon download (%s): %s", r.Path, err.Error())
+							return
+						}
+						select {
+						case c <- b:
+						default:
+						}
+					}
+				case actionAgendaSet:
+					var items []agendaItem
+					err := json.Unmarshal([]byte(m.Data), &items)
+					if err != nil {
+						log.Printf("set agenda (%s): can not parse '%s': %s", r.Path, m.Data, err.Error())
+						return
+					}
+					err = r.setAgenda(items)
+					if err != nil {
+						log.Printf("set agenda (%s): %s", r.Path, err.Error())
+					}
+				case actionAgendaNext, actionAgendaPrev, actionAgendaGoto:
+					var err error
+					switch m.Action {
+					case actionAgendaNext:
+						err = r.agendaStep(1)
+					case actionAgendaPrev:
+						err = r.agendaStep(-1)
+					case actionAgendaGoto:
+						var position int
+						position, err = strconv.Atoi(m.Data)
+						if err == nil {
+							err = r.agendaGoto(position)
+						}
+					}
+					if err != nil {
+						log.Printf("agenda (%s): %s", r.Path, err.Error())
+					}
+				case actionAgendaStop:
+					r.agendaStop()
 				case actionActivate:
+					err := r.activatePlugin(m.From, m.Data)
+					if err != nil {
+						log.Printf("error activating plugin %s (%s): %s", m.From, r.Path, err.Error())
+						return
+					}
+				case actionBreakout:
+					data, err := r.storeEmbeddedImages(m.Data)
+					if err != nil {
+						log.Printf("error activating breakout rooms for plugin %s (%s): %s", m.From, r.Path, err.Error())
+						r.sendAdminError(fmt.Sprintf("%s: %s", translation.GetDefaultTranslation().BreakoutFailed, err.Error()))
+						return
+					}
+					groups := r.groups
+					if gp, ok := r.currentPlugin.(registry.GroupingPlugin); ok {
+						groups = gp.GetGroups()
+					}
+					// Activate the rooms before tearing down the current plugin so it stays usable on error
+					rooms, err := newBreakoutRooms(m.From, []byte(data), groups)
+					if err != nil {
+						log.Printf("error activating breakout rooms for plugin %s (%s): %s", m.From, r.Path, err.Error())
+						r.sendAdminError(fmt.Sprintf("%s: %s", translation.GetDefaultTranslation().BreakoutFailed, err.Error()))
+						return
+					}
+					r.groups = groups
 					if r.currentPlugin != nil {
 						// Reset
 						r.currentPlugin.Deactivate()
@@ -349,55 +719,49 @@ func (r *response) responseMain() {
 						r.userData = nil
 						r.adminInput = nil
 						r.userInput = nil
+						r.participantInput = nil
+						r.participantData = nil
 					}
-					fp, ok := registry.GetFeedbackPlugins(m.From)
-					if !ok {
-						log.Printf("error unknown plugin %s (%s)", m.From, r.Path)
-						return
-					}
-					r.adminHTML = make(chan template.HTML, bufferSize)
-					r.userHTML = make(chan template.HTML, bufferSize)
-					r.adminInput = make(chan []byte, bufferSize)
-					r.userInput = make(chan []byte, bufferSize)
-					p := fp()
-					p.AdminHTMLChannel(r.adminHTML)
-					p.UserHTMLChannel(r.userHTML)
-					p.ReceiveAdminChannel(r.adminInput)
-					p.ReceiveUserChannel(r.userInput)
-					if p, ok := p.(registry.DataFeedbackPlugin); ok {
-						r.adminData = make(chan []byte, bufferSize)
-						r.userData = make(chan []byte, bufferSize)
-						p.AdminDataChannel(r.adminData)
-						p.UserDataChannel(r.userData)
-					}
-					err := p.Activate([]byte(m.Data))
-					if err != nil {
-						log.Printf("error activating plugin %s (%s): %s", m.From, r.Path, err.Error())
-						r.adminHTML = nil
-						r.userHTML = nil
-						r.adminData = nil
-						r.userData = nil
-						r.adminInput = nil
-						r.userInput = nil
-						return
-					}
-					r.currentPlugin = p
-					r.currentPluginName = m.From
-					if _, ok := p.(registry.DownloadResultPlugin); ok {
-						m := message{From: globalAction, Action: canDownload, Data: ""}
-						b, err := json.Marshal(m)
+					r.stopBreakout()
+					r.startBreakout(m.From, rooms)
+
+					messages := r.breakoutAdminMessages()
+					for i := range messages {
+						b, err := json.Marshal(&messages[i])
 						if err != nil {
-							log.Printf("sending candownload (%s): %s", r.Path, err.Error())
-						} else {
-							for k := range r.admins {
-								select {
-								case r.admins[k] <- b:
-								default:
-								}
+							log.Printf("admin HTML (%s) breakout %s: %s", r.Path, r.breakoutPluginName, err.Error())
+							continue
+						}
+						for k := range r.admins {
+							select {
+							case r.admins[k] <- b:
+							default:
 							}
 						}
 					}
+					for k := range r.users {
+						m := message{From: r.breakoutPluginName, Action: actionHTML, Data: string(r.breakoutUserHTML(r.participants[k]))}
+						b, err := json.Marshal(&m)
+						if err != nil {
+							log.Printf("user HTML (%s) breakout %s: %s", r.Path, r.breakoutPluginName, err.Error())
+							continue
+						}
+						select {
+						case r.users[k] <- b:
+						default:
+						}
+					}
 				case actionAdminUpdate:
+					if m.Room != "" {
+						room := r.breakoutRoomByName(m.Room)
+						if room != nil && m.From == r.breakoutPluginName {
+							select {
+							case room.adminInput <- []byte(m.Data):
+							default:
+							}
+						}
+						return
+					}
 					if m.From == r.currentPluginName {
 						select {
 						case r.adminInput <- []byte(m.Data):
@@ -406,11 +770,38 @@ func (r *response) responseMain() {
 					}
 				case actionAdminDownload:
 					dp, ok := r.currentPlugin.(registry.DownloadResultPlugin)
-					if ok {
-						result := dp.GetAdminDownload()
+					if ok || len(r.breakouts) != 0 {
+						var result []byte
+						action := downloadData
+						if len(r.breakouts) != 0 {
+							result = r.breakoutDownload()
+						} else if m.Data != "" {
+							fp, ok := dp.(registry.DownloadFormatPlugin)
+							if !ok || !slices.Contains(fp.DownloadFormats(), m.Data) {
+								break
+							}
+							data := fp.GetAdminDownloadFormat(m.Data)
+							if m.Data == "html" {
+								var err error
+								data, err = downloadDocument(r.currentPluginName, data)
+								if err != nil {
+									log.Printf("sending download (%s): %s", r.Path, err.Error())
+									break
+								}
+							}
+							b, err := json.Marshal(formatDownload{Format: m.Data, Data: string(data)})
+							if err != nil {
+								log.Printf("sending download (%s): %s", r.Path, err.Error())
+								break
+							}
+							result = b
+							action = downloadFormat
+						} else {
+							result = dp.GetAdminDownload()
+						}
 						c, ok := r.admins[b.ID]
 						if ok {
-							m := message{From: globalAction, Action: downloadData, Data: string(result)}
+							m := message{From: globalAction, Action: action, Data: string(result)}
 							b, err := json.Marshal(m)
 							if err != nil {
 								log.Printf("sending download (%s): %s", r.Path, err.Error())
@@ -441,25 +832,45 @@ func (r *response) responseMain() {
 				}
 				switch m.Action {
 				case actionIcon:
-					switch m.Data {
-					case iconSlower:
-						r.nSlower++
-						r.sendIconUpdate(iconSlower, r.nSlower)
-					case iconBreak:
-						r.nBreak++
-						r.sendIconUpdate(iconBreak, r.nBreak)
-					case iconFaster:
-						r.nFaster++
-						r.sendIconUpdate(iconFaster, r.nFaster)
-					case iconQuestion:
-						r.nQuestion++
-						r.sendIconUpdate(iconQuestion, r.nQuestion)
-					case iconGood:
-						r.nGood++
-						r.sendIconUpdate(iconGood, r.nGood)
+					for i := range r.icons {
+						if r.icons[i].Name == m.Data {
+							r.iconCounts[m.Data]++
+							if len(r.iconEvents) >= maxIconEvents {
+								r.iconEvents = r.iconEvents[1:]
+							}
+							r.iconEvents = append(r.iconEvents, iconEvent{Time: time.Now(), Name: m.Data, Label: r.icons[i].Label})
+							r.iconTimelineChanged = true
+							r.sendIconUpdate(m.Data)
+							break
+						}
 					}
 				case actionUserUpdate:
+					if len(r.breakouts) != 0 {
+						room := r.breakoutRoomOf(r.participants[b.ID])
+						if room == nil || m.From != r.breakoutPluginName {
+							return
+						}
+						if room.participantInput != nil {
+							select {
+							case room.participantInput <- registry.ParticipantInput{Participant: r.participants[b.ID], Data: []byte(m.Data)}:
+							default:
+							}
+							return
+						}
+						select {
+						case room.userInput <- []byte(m.Data):
+						default:
+						}
+						return
+					}
 					if m.From == r.currentPluginName {
+						if r.participantInput != nil {
+							select {
+							case r.participantInput <- registry.ParticipantInput{Participant: r.participants[b.ID], Data: []byte(m.Data)}:
+							default:
+							}
+							return
+						}
 						select {
 						case r.userInput <- []byte(m.Data):
 						default:
@@ -546,18 +957,57 @@ func (r *response) responseMain() {
 					}
 				}
 			}()
+		case t := <-r.participantData:
+			func() {
+				r.l.Lock()
+				defer r.l.Unlock()
+
+				m := message{From: r.currentPluginName, Action: actionData, Data: string(t.Data)}
+				b, err := json.Marshal(&m)
+				if err != nil {
+					log.Printf("participant data (%s) plugin %s: %s", r.Path, r.currentPluginName, err.Error())
+					return
+				}
+
+				for k := range r.users {
+					if r.participants[k] != t.Participant {
+						continue
+					}
+					select {
+					case r.users[k] <- b:
+					default:
+					}
+				}
+			}()
 		case <-updateUserTicker.C:
 			func() {
 				r.l.Lock()
 				defer r.l.Unlock()
 
-				r.sendIconUpdate(numberConnected, len(r.users))
+				r.sendConnectedUpdate()
+				for i := range r.icons {
+					if !r.iconModes[r.icons[i].Name].isTotal() {
+						r.sendIconUpdate(r.icons[i].Name)
+					} else {
+						// The number of participants might have changed
+						r.checkIconThreshold(r.icons[i].Name, r.iconValue(r.icons[i].Name))
+					}
+				}
+				if r.iconTimelineChanged {
+					r.iconTimelineChanged = false
+					for k := range r.admins {
+						r.sendIconTimeline(r.admins[k])
+					}
+				}
 			}()
+		case <-agendaTicker.C:
+			r.agendaTick()
 		case <-done:
 			// Function to use defer
 			func() {
 				r.l.Lock()
 				defer r.l.Unlock()
+				r.stopBreakout()
 				if r.currentPlugin != nil {
 					// Reset plugin
 					r.currentPlugin.Deactivate()
@@ -569,19 +1019,173 @@ func (r *response) responseMain() {
 					r.userData = nil
 					r.adminInput = nil
 					r.userInput = nil
+					r.participantInput = nil
+					r.participantData = nil
 				}
 			}()
+			r.images.Clear()
 			log.Printf("stopping %s", r.Path)
 			return
 		}
 	}
 }
 
-func (r *response) sendIconUpdate(icon string, data int) {
-	m := message{From: globalAction, Action: icon, Data: strconv.Itoa(data)}
+// activatePlugin replaces the current plugin (and all breakout rooms) with a new instance of a plugin. Caller must hold r.l.
+func (r *response) activatePlugin(pluginName, data string) error {
+	data, err := r.storeEmbeddedImages(data)
+	if err != nil {
+		return err
+	}
+	r.stopBreakout()
+	if r.currentPlugin != nil {
+		// Reset
+		if gp, ok := r.currentPlugin.(registry.GroupingPlugin); ok {
+			r.groups = gp.GetGroups()
+		}
+		r.currentPlugin.Deactivate()
+		r.currentPlugin = nil
+		r.currentPluginName = ""
+		r.adminHTML = nil
+		r.userHTML = nil
+		r.adminData = nil
+		r.userData = nil
+		r.adminInput = nil
+		r.userInput = nil
+		r.participantInput = nil
+		r.participantData = nil
+	}
+	fp, ok := registry.GetFeedbackPlugins(pluginName)
+	if !ok {
+		return fmt.Errorf("unknown plugin %s", pluginName)
+	}
+	r.adminHTML = make(chan template.HTML, bufferSize)
+	r.userHTML = make(chan template.HTML, bufferSize)
+	r.adminInput = make(chan []byte, bufferSize)
+	r.userInput = make(chan []byte, bufferSize)
+	p := fp()
+	p.AdminHTMLChannel(r.adminHTML)
+	p.UserHTMLChannel(r.userHTML)
+	p.ReceiveAdminChannel(r.adminInput)
+	p.ReceiveUserChannel(r.userInput)
+	if p, ok := p.(registry.DataFeedbackPlugin); ok {
+		r.adminData = make(chan []byte, bufferSize)
+		r.userData = make(chan []byte, bufferSize)
+		p.AdminDataChannel(r.adminData)
+		p.UserDataChannel(r.userData)
+	}
+	if p, ok := p.(registry.ParticipantFeedbackPlugin); ok {
+		r.participantInput = make(chan registry.ParticipantInput, bufferSize)
+		p.ReceiveUserParticipantChannel(r.participantInput)
+	}
+	if p, ok := p.(registry.ParticipantDataFeedbackPlugin); ok {
+		r.participantData = make(chan registry.ParticipantOutput, bufferSize)
+		p.UserParticipantDataChannel(r.participantData)
+	}
+	err = p.Activate([]byte(data))
+	if err != nil {
+		r.adminHTML = nil
+		r.userHTML = nil
+		r.adminData = nil
+		r.userData = nil
+		r.adminInput = nil
+		r.userInput = nil
+		r.participantInput = nil
+		r.participantData = nil
+		return err
+	}
+	r.currentPlugin = p
+	r.currentPluginName = pluginName
+	if _, ok := p.(registry.DownloadResultPlugin); ok {
+		formats := []string{}
+		if fp, ok := p.(registry.DownloadFormatPlugin); ok {
+			formats = fp.DownloadFormats()
+		}
+		f, err := json.Marshal(formats)
+		if err != nil {
+			log.Printf("sending candownload (%s): %s", r.Path, err.Error())
+		}
+		m := message{From: globalAction, Action: canDownload, Data: string(f)}
+		b, err := json.Marshal(m)
+		if err != nil {
+			log.Printf("sending candownload (%s): %s", r.Path, err.Error())
+		} else {
+			for k := range r.admins {
+				select {
+				case r.admins[k] <- b:
+				default:
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// iconValue returns the current value of the counter of an icon. Caller must hold r.l.
+func (r *response) iconValue(name string) float64 {
+	mode := r.iconModes[name]
+	if mode.isTotal() {
+		return float64(r.iconCounts[name])
+	}
+	return helper.RoundFloat(iconValue(r.iconEvents, name, r.iconReset, mode, time.Now()), 1)
+}
+
+// checkIconThreshold alerts all admins if the threshold of an icon is reached for the first time since the last reset.
+// Caller must hold r.l.
+func (r *response) checkIconThreshold(name string, value float64) {
+	if r.iconAlerted[name] {
+		return
+	}
+	t := r.iconThresholds[name]
+	limit, ok := t.limit(len(r.users))
+	if !ok || value < limit {
+		return
+	}
+	r.iconAlerted[name] = true
+
+	alert := iconAlert{Name: name, Count: value, Threshold: helper.RoundFloat(limit, 1)}
+	for i := range r.icons {
+		if r.icons[i].Name == name {
+			alert.Label = r.icons[i].Label
+			break
+		}
+	}
+	if t.Log {
+		log.Printf("icon alert (%s): %s reached %s (threshold %s)", r.Path, alert.Label, helper.FormatFloat(value), helper.FormatFloat(limit))
+	}
+	d, err := json.Marshal(alert)
+	if err != nil {
+		log.Printf("sending icon alert (%s): %s", r.Path, err.Error())
+		return
+	}
+	m := message{From: globalAction, Action: iconAlertData, Data: string(d)}
+	b, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("sending icon alert (%s): %s", r.Path, err.Error())
+		return
+	}
+	for k := range r.admins {
+		select {
+		case r.admins[k] <- b:
+		default:
+		}
+	}
+}
+
+// sendIconUpdate sends the current count of an icon to all admins and checks the threshold of the icon. Caller must hold r.l.
+func (r *response) sendIconUpdate(name string) {
+	mode := r.iconModes[name]
+	count := r.iconValue(name)
+	r.checkIconThreshold(name, count)
+	d, err := json.Marshal(iconCountUpdate{Name: name, Count: count, Mode: mode.Mode, Minutes: mode.Minutes, Threshold: r.iconThresholds[name]})
+	if err != nil {
+		log.Printf("sending icons (%s): %s", r.Path, err.Error())
+		return
+	}
+	m := message{From: globalAction, Action: iconCount, Data: string(d)}
 	b, err := json.Marshal(m)
 	if err != nil {
 		log.Printf("sending icons (%s): %s", r.Path, err.Error())
+		return
 	}
 	for k := range r.admins {
 		select {
@@ -591,6 +1195,76 @@ func (r *response) sendIconUpdate(icon string, data int) {
 	}
 }
 
+// sendAdminError shows an error message to all admins. Caller must hold r.l.
+func (r *response) sendAdminError(text string) {
+	m := message{From: globalAction, Action: errorMessage, Data: text}
+	b, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("sending error (%s): %s", r.Path, err.Error())
+		return
+	}
+	for k := range r.admins {
+		select {
+		case r.admins[k] <- b:
+		default:
+		}
+	}
+}
+
+// sendConnectedUpdate sends the number of connected participants to all admins. Caller must hold r.l.
+func (r *response) sendConnectedUpdate() {
+	m := message{From: globalAction, Action: numberConnected, Data: strconv.Itoa(len(r.users))}
+	b, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("sending connected (%s): %s", r.Path, err.Error())
+		return
+	}
+	for k := range r.admins {
+		select {
+		case r.admins[k] <- b:
+		default:
+		}
+	}
+}
+
+// sendIconTimeline sends the activity of all icons over time to a single connection. Caller must hold r.l.
+func (r *response) sendIconTimeline(c chan<- []byte) {
+	d, err := json.Marshal(getIconTimeline(r.iconEvents))
+	if err != nil {
+		log.Printf("sending icon timeline (%s): %s", r.Path, err.Error())
+		return
+	}
+	m := message{From: globalAction, Action: iconActivity, Data: string(d)}
+	b, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("sending icon timeline (%s): %s", r.Path, err.Error())
+		return
+	}
+	select {
+	case c <- b:
+	default:
+	}
+}
+
+// sendIcons sends the current icon set to a single connection. Caller must hold r.l.
+func (r *response) sendIcons(c chan<- []byte) {
+	d, err := json.Marshal(r.icons)
+	if err != nil {
+		log.Printf("sending icon set (%s): %s", r.Path, err.Error())
+		return
+	}
+	m := message{From: globalAction, Action: icons, Data: string(d)}
+	b, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("sending icon set (%s): %s", r.Path, err.Error())
+		return
+	}
+	select {
+	case c <- b:
+	default:
+	}
+}
+
 func fetchConfigCache() {
 	pluginConfigCacheOnce.Do(func() {
 		plugins := registry.GetNamesOfFeedbackPlugins()
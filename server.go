@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020,2021,2025 Marcus Soll
+// Copyright 2020,2021,2025,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -78,6 +78,7 @@ func init() {
 
 type authenticateTemplateStruct struct {
 	Key         string
+	IconPresets []iconPresetOption
 	Translation translation.Translation
 	ServerPath  string
 }
@@ -226,11 +227,13 @@ func rootHandle(rw http.ResponseWriter, r *http.Request) {
 
 	response, ok := responseCache[key]
 	if !ok {
+		owner := ""
 		if config.NeedAuthenticationForNew {
 			switch r.Method {
 			case http.MethodGet:
 				// Send authentification request
-				td := authenticateTemplateStruct{Key: key, Translation: translation.GetDefaultTranslation(), ServerPath: config.ServerPath}
+				tl := translation.GetDefaultTranslation()
+				td := authenticateTemplateStruct{Key: key, IconPresets: iconPresetOptions(tl), Translation: tl, ServerPath: config.ServerPath}
 				authenticateTemplate.Execute(rw, td)
 				return
 			case http.MethodPost:
@@ -268,6 +271,11 @@ func rootHandle(rw http.ResponseWriter, r *http.Request) {
 					return
 				}
 				// All ok - continue creation
+				owner = username
+				err = NewSession(rw, username)
+				if err != nil {
+					log.Printf("can not create session for '%s': %s", username, err.Error())
+				}
 				if config.LogLogin {
 					log.Printf("Creating new response for '%s': %s", username, key)
 				}
@@ -289,13 +297,21 @@ func rootHandle(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 		password := base32.StdEncoding.EncodeToString(b)
-		response = NewResponse(key, password)
+		response = NewResponse(key, password, r.FormValue("icons"), owner)
 		responseCache[key] = response
 
 		http.Redirect(rw, r, fmt.Sprintf("/%s?admin=%s", key, password), http.StatusFound)
 		return
 	}
 
+	if id := r.URL.Query().Get("image"); id != "" {
+		// image - don't block if reading from disk takes long
+		responseCacheLock.Unlock()
+		response.ServeImage(rw, id)
+		responseCacheLock.Lock()
+		return
+	}
+
 	pw, ws := r.URL.Query().Get("admin"), r.URL.Query().Get("ws")
 	if pw != "" {
 		// Admin connection
@@ -309,9 +325,41 @@ func rootHandle(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if r.URL.Query().Get("upload") != "" {
+			// upload - don't block if receiving takes long
+			responseCacheLock.Unlock()
+			response.HandleUpload(rw, r)
+			responseCacheLock.Lock()
+			return
+		}
+
+		if r.URL.Query().Get("library") != "" {
+			// library - don't block while reading the request
+			responseCacheLock.Unlock()
+			response.HandleLibrary(rw, r)
+			responseCacheLock.Lock()
+			return
+		}
+
+		if r.URL.Query().Get("import") != "" {
+			// import - don't block while reading the request
+			responseCacheLock.Unlock()
+			response.HandleImport(rw, r)
+			responseCacheLock.Lock()
+			return
+		}
+
+		if r.URL.Query().Get("agenda") != "" {
+			// agenda - don't block while the plugin is activated
+			responseCacheLock.Unlock()
+			response.HandleAgenda(rw, r)
+			responseCacheLock.Lock()
+			return
+		}
+
 		if ws == "" {
 			// no websocket
-			response.WriteAdminPage(rw)
+			response.WriteAdminPage(rw, r)
 			return
 		}
 
@@ -336,12 +384,14 @@ func rootHandle(rw http.ResponseWriter, r *http.Request) {
 	// User connection
 	if ws == "" {
 		// no websocket
+		GetParticipantID(rw, r, key)
 		response.WriteUserPage(rw)
 		return
 	}
 
 	// websocket - don't block ih waiting takes long
 	responseCacheLock.Unlock()
+	participant := GetParticipantID(rw, r, key)
 	conn, err := upgrader.Upgrade(rw, r, nil)
 	responseCacheLock.Lock()
 	if err != nil {
@@ -354,7 +404,7 @@ func rootHandle(rw http.ResponseWriter, r *http.Request) {
 		conn.Close()
 		return
 	}
-	response.AddUser(conn)
+	response.AddUser(conn, participant, GetRealIP(r))
 }
 
 // RunServer starts the actual server.
@@ -423,6 +473,7 @@ func gc(ctx context.Context) {
 				}
 			}
 			responseCacheLock.Unlock()
+			i += cleanSessions()
 			log.Printf("server: gc freed %d ressources", i)
 		case <-done:
 			log.Println("server: stopping gc")
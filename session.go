@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	sessionCookie   = "responsego_session"
+	sessionDuration = 12 * time.Hour
+)
+
+// session is the login of an authenticated user.
+type session struct {
+	User    string
+	Expires time.Time
+}
+
+var sessions = make(map[string]session)
+var sessionsLock = sync.Mutex{}
+
+// NewSession creates a session for an authenticated user and sets it as a cookie.
+// The session is valid for all responses.
+func NewSession(rw http.ResponseWriter, user string) error {
+	b := make([]byte, 35)
+	_, err := rand.Read(b)
+	if err != nil {
+		return err
+	}
+	id := base32.StdEncoding.EncodeToString(b)
+
+	sessionsLock.Lock()
+	sessions[id] = session{User: user, Expires: time.Now().Add(sessionDuration)}
+	sessionsLock.Unlock()
+
+	http.SetCookie(rw, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    id,
+		Path:     rootPath,
+		MaxAge:   int(sessionDuration / time.Second),
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return nil
+}
+
+// GetSessionUser returns the authenticated user of the request.
+// The second return value is false if the request does not belong to a valid session.
+func GetSessionUser(r *http.Request) (string, bool) {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return "", false
+	}
+
+	sessionsLock.Lock()
+	defer sessionsLock.Unlock()
+	s, ok := sessions[c.Value]
+	if !ok || time.Now().After(s.Expires) {
+		return "", false
+	}
+	return s.User, true
+}
+
+// cleanSessions removes all expired sessions and returns the number of removed sessions.
+func cleanSessions() int {
+	sessionsLock.Lock()
+	defer sessionsLock.Unlock()
+	now := time.Now()
+	i := 0
+	for k := range sessions {
+		if now.After(sessions[k].Expires) {
+			delete(sessions, k)
+			i++
+		}
+	}
+	return i
+}
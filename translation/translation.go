@@ -1,5 +1,5 @@
 // SPDX-License-Identifier: Apache-2.0
-// Copyright 2020,2023 Marcus Soll
+// Copyright 2020,2023,2026 Marcus Soll
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -26,49 +26,248 @@ import (
 
 // Translation represents an object holding all translations
 type Translation struct {
-	Language              string
-	CreatedBy             string
-	Impressum             string
-	PrivacyPolicy         string
-	ParticipantLink       string
-	Faster                string
-	Break                 string
-	Slower                string
-	Question              string
-	Good                  string
-	NoConnection          string
-	Activate              string
-	DisplayText           string
-	DisplayQuestion       string
-	Submit                string
-	Submitted             string
-	Finish                string
-	DisplayBlank          string
-	DisplayFreeText       string
-	FreeTextQuestion      string
-	DisplayWordcloud      string
-	DisplayRandomGroup    string
-	DisplayMultipleChoice string
-	DisplayNumber         string
-	DisplayTimeQuestion   string
-	UpdateAll5Seconds     string
-	TabActiveContent      string
-	TabElements           string
-	TabSavedElements      string
-	SaveElement           string
-	DownloadButton        string
-	ClearElements         string
-	ReplaceElements       string
-	ResponseSent          string
-	Username              string
-	Password              string
-	Authenticate          string
-	CopyToClipboard       string
-	Title                 string
-	Seperator             string
-	CurrentlyConnected    string
-	Minutes               string
-	Precision             string
+	Language                string
+	CreatedBy               string
+	Impressum               string
+	PrivacyPolicy           string
+	ParticipantLink         string
+	Faster                  string
+	Break                   string
+	Slower                  string
+	Question                string
+	Good                    string
+	NoConnection            string
+	Activate                string
+	DisplayText             string
+	DisplayQuestion         string
+	Submit                  string
+	Submitted               string
+	Finish                  string
+	DisplayBlank            string
+	DisplayFreeText         string
+	FreeTextQuestion        string
+	DisplayWordcloud        string
+	DisplayRandomGroup      string
+	DisplayMultipleChoice   string
+	DisplayNumber           string
+	DisplayTimeQuestion     string
+	UpdateAll5Seconds       string
+	TabActiveContent        string
+	TabElements             string
+	TabSavedElements        string
+	SaveElement             string
+	DownloadButton          string
+	ClearElements           string
+	ReplaceElements         string
+	ResponseSent            string
+	Username                string
+	Password                string
+	Authenticate            string
+	CopyToClipboard         string
+	Title                   string
+	Seperator               string
+	CurrentlyConnected      string
+	Minutes                 string
+	Precision               string
+	Minimum                 string
+	Maximum                 string
+	AllowDecimal            string
+	TrimOutliers            string
+	HistogramBins           string
+	CorrectValue            string
+	RevealCorrectValue      string
+	Mean                    string
+	Median                  string
+	StandardDeviation       string
+	Percentile              string
+	Trimmed                 string
+	TimeLimit               string
+	Seconds                 string
+	TimeRemaining           string
+	DisplayCountdown        string
+	Start                   string
+	Pause                   string
+	Reset                   string
+	TimeIsUp                string
+	StartImmediately        string
+	DisplayAppointment      string
+	AppointmentSlots        string
+	Add                     string
+	Duration                string
+	Name                    string
+	Yes                     string
+	Maybe                   string
+	No                      string
+	DisplayImageChoice      string
+	Upload                  string
+	Caption                 string
+	Remove                  string
+	DisplayHotspot          string
+	ClickOnImage            string
+	ShowPoints              string
+	Clusters                string
+	WordlistLanguage        string
+	RemoveStopWords         string
+	FilterProfanity         string
+	ReviewBeforeDisplay     string
+	SubmissionLimit         string
+	RemainingSubmissions    string
+	Moderation              string
+	Filtered                string
+	Banned                  string
+	Visible                 string
+	Pending                 string
+	Hidden                  string
+	Approve                 string
+	Hide                    string
+	Ban                     string
+	Unban                   string
+	Merge                   string
+	MergeInto               string
+	PublishedAnswers        string
+	Like                    string
+	PublishStarred          string
+	UnpublishAll            string
+	GroupByKeywords         string
+	Ungroup                 string
+	Other                   string
+	Published               string
+	Star                    string
+	Unstar                  string
+	Publish                 string
+	Unpublish               string
+	Unhide                  string
+	DisplayBoard            string
+	BoardColumns            string
+	VotesPerParticipant     string
+	BoardCollectPhase       string
+	BoardVotePhase          string
+	RemainingVotes          string
+	StartVoting             string
+	Votes                   string
+	Vote                    string
+	BoardKeep               string
+	BoardStop               string
+	BoardStart              string
+	DisplayNPS              string
+	NPSDefaultQuestion      string
+	AskForReason            string
+	CompareWithPrevious     string
+	NotLikely               string
+	ExtremelyLikely         string
+	Reason                  string
+	Previous                string
+	Current                 string
+	Detractors              string
+	Passives                string
+	Promoters               string
+	DisplayMatrix           string
+	MatrixRows              string
+	MatrixColumns           string
+	MultipleAnswers         string
+	DisplaySurvey           string
+	SurveySingle            string
+	SurveyMulti             string
+	SurveyNumber            string
+	SurveyText              string
+	SurveyScale             string
+	SurveyOptions           string
+	SurveyClosed            string
+	AllResponses            string
+	SurveyBack              string
+	SurveyNext              string
+	GroupCapacities         string
+	GroupWaiting            string
+	GroupsFull              string
+	Reshuffle               string
+	WithoutGroup            string
+	Assignment              string
+	BreakoutRooms           string
+	BreakoutRoom            string
+	NoBreakoutRoom          string
+	ActivateInBreakoutRooms string
+	TimeQuestionMode        string
+	TimeOfDay               string
+	DateAndTime             string
+	TimeRange               string
+	DateAndTimeRange        string
+	IntervalLength          string
+	TimeFrom                string
+	TimeTo                  string
+	Agreement               string
+	MostAgreedInterval      string
+	DisplayReactions        string
+	ReactionsEmoji          string
+	ReactionsLimit          string
+	Timeline                string
+	IconPresetLecture       string
+	IconPresetWorkshop      string
+	IconPresetYesNo         string
+	Neutral                 string
+	Bad                     string
+	Unsure                  string
+	FeedbackIcons           string
+	CustomIcons             string
+	Label                   string
+	SetIcons                string
+	ResetIconCount          string
+	CounterMode             string
+	CounterTotal            string
+	CounterWindow           string
+	CounterDecay            string
+	CounterMinutes          string
+	AllIcons                string
+	Apply                   string
+	IconActivity            string
+	DownloadIconEvents      string
+	IconThreshold           string
+	ThresholdAbsolute       string
+	ThresholdPercent        string
+	LogAlerts               string
+	AlertSound              string
+	AlertVibration          string
+	ThresholdReached        string
+	Dismiss                 string
+	Agenda                  string
+	AgendaStop              string
+	AddToAgenda             string
+	AgendaDuration          string
+	AgendaAfterClose        string
+	AgendaRemaining         string
+	AgendaShortcuts         string
+	AgendaSave              string
+	Next                    string
+	Library                 string
+	LibraryShared           string
+	LibraryFilter           string
+	LibraryImportBrowser    string
+	LibraryImport           string
+	LibraryCopy             string
+	Description             string
+	Folder                  string
+	NoFolder                string
+	Tags                    string
+	ShareWith               string
+	Edit                    string
+	Delete                  string
+	True                    string
+	False                   string
+	ImportQuestions         string
+	ImportDetectFormat      string
+	ImportProblems          string
+	LoadDeck                string
+	Answers                 string
+	Markdown                string
+	RevealMode              string
+	RevealAdmin             string
+	RevealLive              string
+	RevealHidden            string
+	RevealNever             string
+	ResultsHidden           string
+	ImageEmbedFailed        string
+	PointsPerParticipant    string
+	RemainingPoints         string
+	BreakoutFailed          string
 }
 
 const defaultLanguage = "en"
@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Top-Ranger/responsego/helper"
+)
+
+const (
+	defaultMaxUploadSizeKB   = 2048
+	maxImagesPerResponse     = 200
+	uploadFormField          = "image"
+	uploadMultipartOverheadB = 1024 * 1024
+)
+
+// allowedImageTypes contains all content types which can be uploaded.
+// SVG is not allowed since it can contain scripts.
+var allowedImageTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// embeddedImageRegexp matches images embedded as data URL, e.g. in saved elements.
+var embeddedImageRegexp = regexp.MustCompile(`data:image/(?:png|jpeg|gif|webp);base64,[A-Za-z0-9+/]+=*`)
+
+type storedImage struct {
+	ContentType string
+	Data        []byte // nil if stored on disk
+}
+
+// imageStore holds all images uploaded to a response.
+// Images are stored in memory if dir is empty, else on disk inside dir.
+// It is safe for concurrent usage.
+type imageStore struct {
+	l      sync.Mutex
+	dir    string
+	images map[string]storedImage
+	hashes map[[sha256.Size]byte]string
+}
+
+type uploadResult struct {
+	ID  string
+	URL string
+}
+
+func newImageStore(dir string) *imageStore {
+	return &imageStore{
+		dir:    dir,
+		images: make(map[string]storedImage),
+		hashes: make(map[[sha256.Size]byte]string),
+	}
+}
+
+func maxUploadSize() int64 {
+	if config.MaxUploadSizeKB <= 0 {
+		return defaultMaxUploadSizeKB * 1024
+	}
+	return int64(config.MaxUploadSizeKB) * 1024
+}
+
+// Add stores an image. It returns the ID of the image.
+// If the same image is already stored, the ID of the stored image is returned.
+func (s *imageStore) Add(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := allowedImageTypes[contentType]
+	if !ok {
+		return "", fmt.Errorf("image type %s not allowed", contentType)
+	}
+	hash := sha256.Sum256(data)
+
+	b := make([]byte, 15)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	id := base32.StdEncoding.EncodeToString(b)
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if id, ok := s.hashes[hash]; ok {
+		return id, nil
+	}
+	if len(s.images) >= maxImagesPerResponse {
+		return "", errors.New("too many images uploaded")
+	}
+
+	if s.dir == "" {
+		s.images[id] = storedImage{ContentType: contentType, Data: data}
+		s.hashes[hash] = id
+		return id, nil
+	}
+
+	err = os.WriteFile(filepath.Join(s.dir, id+ext), data, 0600)
+	if err != nil {
+		return "", err
+	}
+	s.images[id] = storedImage{ContentType: contentType}
+	s.hashes[hash] = id
+	return id, nil
+}
+
+// Get returns an image and its content type. The bool is false if the image does not exist.
+func (s *imageStore) Get(id string) ([]byte, string, bool) {
+	s.l.Lock()
+	img, ok := s.images[id]
+	s.l.Unlock()
+	if !ok {
+		return nil, "", false
+	}
+	if img.Data != nil {
+		return img.Data, img.ContentType, true
+	}
+
+	b, err := os.ReadFile(filepath.Join(s.dir, id+allowedImageTypes[img.ContentType]))
+	if err != nil {
+		log.Printf("image store: can not read image %s: %s", id, err.Error())
+		return nil, "", false
+	}
+	return b, img.ContentType, true
+}
+
+// Clear removes all images, including those stored on disk.
+func (s *imageStore) Clear() {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	for id, img := range s.images {
+		if img.Data == nil {
+			err := os.Remove(filepath.Join(s.dir, id+allowedImageTypes[img.ContentType]))
+			if err != nil {
+				log.Printf("image store: can not remove image %s: %s", id, err.Error())
+			}
+		}
+	}
+	s.images = make(map[string]storedImage)
+	s.hashes = make(map[[sha256.Size]byte]string)
+}
+
+// storeEmbeddedImages stores all images embedded as data URL in the configuration of an element and replaces them with the URL of the stored image.
+// This allows saved elements to contain their images, since uploaded images only exist as long as the response.
+func (r *response) storeEmbeddedImages(data string) (string, error) {
+	var err error
+	data = embeddedImageRegexp.ReplaceAllStringFunc(data, func(s string) string {
+		if err != nil {
+			return s
+		}
+		encoded := s[len("data:image/"):]
+		encoded = encoded[strings.Index(encoded, ",")+1:]
+		if int64(base64.StdEncoding.DecodedLen(len(encoded))) > maxUploadSize()+3 {
+			err = errors.New("embedded image too large")
+			return s
+		}
+		var b []byte
+		b, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return s
+		}
+		var id string
+		id, err = r.images.Add(b)
+		if err != nil {
+			return s
+		}
+		return helper.InternalImageURL(id)
+	})
+	if err != nil {
+		return "", fmt.Errorf("can not store embedded image: %w", err)
+	}
+	return data, nil
+}
+
+// HandleUpload handles an image upload of an admin.
+// The image has to be send as a multipart form with the field 'image'.
+func (r *response) HandleUpload(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	req.Body = http.MaxBytesReader(rw, req.Body, maxUploadSize()+uploadMultipartOverheadB)
+	f, _, err := req.FormFile(uploadFormField)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxUploadSize()+1))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if int64(len(data)) > maxUploadSize() {
+		http.Error(rw, "image too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	id, err := r.images.Add(data)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	b, err := json.Marshal(uploadResult{ID: id, URL: helper.InternalImageURL(id)})
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(b)
+}
+
+// ServeImage writes an uploaded image.
+func (r *response) ServeImage(rw http.ResponseWriter, id string) {
+	data, contentType, ok := r.images.Get(id)
+	if !ok {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+	rw.Header().Set("Content-Type", contentType)
+	rw.Header().Set("X-Content-Type-Options", "nosniff")
+	rw.Header().Set("Cache-Control", "private, max-age=43200")
+	rw.Write(data)
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wordlist provides per-language lists of stop-words and profanity.
+// Lists are plain text files with one word per line. Empty lines and lines starting with '#' are ignored.
+// Files are named stopwords_<language>.txt and profanity_<language>.txt.
+// Default lists are included in the binary, they can be replaced by files in a directory through LoadDirectory.
+package wordlist
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	stopwordsPrefix = "stopwords_"
+	profanityPrefix = "profanity_"
+	fileSuffix      = ".txt"
+)
+
+//go:embed *.txt
+var defaultFiles embed.FS
+
+var (
+	initialise sync.Once
+	rwlock     sync.RWMutex
+	stopwords  = make(map[string]map[string]bool)
+	profanity  = make(map[string]map[string]bool)
+)
+
+// LoadDirectory loads all word lists from the directory at path.
+// Lists found in the directory replace the default lists of the same language.
+func LoadDirectory(path string) error {
+	initialise.Do(loadDefault)
+	return load(os.DirFS(path))
+}
+
+func loadDefault() {
+	err := load(defaultFiles)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func load(fsys fs.FS) error {
+	files, err := fs.Glob(fsys, "*"+fileSuffix)
+	if err != nil {
+		return err
+	}
+
+	for i := range files {
+		var target map[string]map[string]bool
+		var language string
+		switch {
+		case strings.HasPrefix(files[i], stopwordsPrefix):
+			target = stopwords
+			language = strings.TrimSuffix(strings.TrimPrefix(files[i], stopwordsPrefix), fileSuffix)
+		case strings.HasPrefix(files[i], profanityPrefix):
+			target = profanity
+			language = strings.TrimSuffix(strings.TrimPrefix(files[i], profanityPrefix), fileSuffix)
+		default:
+			continue
+		}
+
+		b, err := fs.ReadFile(fsys, files[i])
+		if err != nil {
+			return err
+		}
+
+		rwlock.Lock()
+		target[language] = parse(b)
+		rwlock.Unlock()
+	}
+	return nil
+}
+
+func parse(b []byte) map[string]bool {
+	words := make(map[string]bool)
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		w := strings.ToLower(strings.TrimSpace(s.Text()))
+		if w == "" || strings.HasPrefix(w, "#") {
+			continue
+		}
+		words[w] = true
+	}
+	return words
+}
+
+// Languages returns all languages with at least one word list.
+// The list is sorted alphabetically.
+func Languages() []string {
+	initialise.Do(loadDefault)
+	rwlock.RLock()
+	defer rwlock.RUnlock()
+
+	known := make(map[string]bool)
+	for k := range stopwords {
+		known[k] = true
+	}
+	for k := range profanity {
+		known[k] = true
+	}
+	l := make([]string, 0, len(known))
+	for k := range known {
+		l = append(l, k)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// IsStopWord returns whether the lower case word is a stop-word in the given language.
+func IsStopWord(language, word string) bool {
+	initialise.Do(loadDefault)
+	rwlock.RLock()
+	defer rwlock.RUnlock()
+	return stopwords[language][word]
+}
+
+// IsProfanity returns whether the lower case word is considered profanity in the given language.
+func IsProfanity(language, word string) bool {
+	initialise.Do(loadDefault)
+	rwlock.RLock()
+	defer rwlock.RUnlock()
+	return profanity[language][word]
+}